@@ -0,0 +1,54 @@
+package postmangen
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RouteSpec describes a route passed to Register. It requires the "method",
+// "path" and "inputType" keys; the other keys are optional route settings.
+type RouteSpec map[string]any
+
+// RegisterAll registers every spec, continuing past routes that fail. The
+// returned error joins the failures, each prefixed with the route's method
+// and path. Routes are generated concurrently when SetParallelism allows it.
+func (p *PostmanGen) RegisterAll(specs ...RouteSpec) error {
+	if p.parallelism > 1 && len(specs) > 1 {
+		return p.registerParallel(specs)
+	}
+
+	errs := []error{}
+	for _, spec := range specs {
+		if err := p.Register(spec); err != nil {
+			errs = append(errs, fmt.Errorf("%v %v: %w", spec["method"], spec["path"], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterMap registers routes keyed by "METHOD /path", e.g.
+// "POST /users", in key order. The key supplies the method and path unless
+// the spec sets them. Failures are handled like in RegisterAll.
+func (p *PostmanGen) RegisterMap(routes map[string]RouteSpec) error {
+	keys := make([]string, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	specs := []RouteSpec{}
+	for _, key := range keys {
+		spec := RouteSpec{}
+		if method, path, ok := strings.Cut(strings.TrimSpace(key), " "); ok {
+			spec["method"] = method
+			spec["path"] = strings.TrimSpace(path)
+		}
+		for k, v := range routes[key] {
+			spec[k] = v
+		}
+		specs = append(specs, spec)
+	}
+	return p.RegisterAll(specs...)
+}
@@ -0,0 +1,32 @@
+package postmangen
+
+import "maps"
+
+// SetProtocolProfileBehavior sets Postman's protocolProfileBehavior flags on
+// every generated item, e.g. map[string]bool{"followRedirects": false,
+// "strictSSL": false, "disableCookies": true}. Flags from repeated calls are
+// merged, and routes can override single flags with the
+// "protocolProfileBehavior" spec key.
+func (p *PostmanGen) SetProtocolProfileBehavior(flags map[string]bool) *PostmanGen {
+	maps.Copy(p.protocolProfile, flags)
+	return p
+}
+
+// protocolProfileBehaviorFor returns the protocolProfileBehavior of a route,
+// or nil if it has no flags. Server-Sent Events routes disable body pruning
+// so a body set on their GET request is still sent.
+func (p *PostmanGen) protocolProfileBehaviorFor(spec map[string]any, stream bool) any {
+	flags := map[string]bool{}
+	if stream {
+		flags["disableBodyPruning"] = true
+	}
+	maps.Copy(flags, p.protocolProfile)
+	if routeFlags, ok := spec["protocolProfileBehavior"].(map[string]bool); ok {
+		maps.Copy(flags, routeFlags)
+	}
+
+	if len(flags) == 0 {
+		return nil
+	}
+	return flags
+}
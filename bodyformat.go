@@ -0,0 +1,174 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SetBodyIndent sets the number of spaces JSON example bodies of requests and
+// saved responses are indented with, 2 by default. Zero writes compact
+// single-line bodies.
+func (p *PostmanGen) SetBodyIndent(width int) *PostmanGen {
+	p.bodyIndent = max(width, 0)
+	return p
+}
+
+// SetPreserveFieldOrder writes the members of JSON example bodies in the
+// order their struct fields are declared, like encoding/json does, instead of
+// alphabetically. Members that do not come from a struct field, such as map
+// entries, follow in alphabetical order.
+func (p *PostmanGen) SetPreserveFieldOrder(enabled bool) *PostmanGen {
+	p.preserveFieldOrder = enabled
+	return p
+}
+
+// exampleField is a JSON member of a struct type.
+type exampleField struct {
+	name string
+	typ  reflect.Type
+}
+
+// encodeJSONBody encodes the example value v of type t, which may be nil if
+// unknown, as configured by SetBodyIndent and SetPreserveFieldOrder.
+func (p *PostmanGen) encodeJSONBody(v any, t reflect.Type) ([]byte, error) {
+	var fields []exampleField
+	if t != nil && derefType(t).Kind() == reflect.Struct {
+		fields = p.exampleFieldOrder(derefType(t))
+	}
+	return p.encodeJSONObject(v, t, fields)
+}
+
+// encodeJSONObject encodes v like encodeJSONBody, ordering the members of a
+// top-level object by fields.
+func (p *PostmanGen) encodeJSONObject(v any, t reflect.Type, fields []exampleField) ([]byte, error) {
+	var node *orderedNode
+	if p.preserveFieldOrder {
+		node = p.orderedExample(v, t, fields)
+	} else {
+		node = p.orderedExample(v, nil, nil)
+	}
+	if p.maxExampleDepth == 0 && p.maxExampleBytes == 0 {
+		return p.formatJSON(node)
+	}
+	return p.limitExample(node)
+}
+
+// formatJSON writes node indented as configured by SetBodyIndent.
+func (p *PostmanGen) formatJSON(node *orderedNode) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := writeOrderedJSON(buf, node); err != nil {
+		return nil, err
+	}
+	if p.bodyIndent == 0 {
+		return bytes.Clone(buf.Bytes()), nil
+	}
+	out := getBuffer()
+	defer putBuffer(out)
+	if err := json.Indent(out, buf.Bytes(), "", indentation(p.bodyIndent)); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(out.Bytes()), nil
+}
+
+// indentation returns width spaces, without allocating for common widths.
+func indentation(width int) string {
+	const spaces = "        "
+	if width <= len(spaces) {
+		return spaces[:width]
+	}
+	return strings.Repeat(" ", width)
+}
+
+// orderedExample converts an example value to an orderedNode, ordering the
+// members of objects by fields, or by the struct fields of t if fields is
+// nil.
+func (p *PostmanGen) orderedExample(v any, t reflect.Type, fields []exampleField) *orderedNode {
+	if t != nil {
+		t = derefType(t)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		var elem reflect.Type
+		if t != nil {
+			switch t.Kind() {
+			case reflect.Struct:
+				if fields == nil {
+					fields = p.exampleFieldOrder(t)
+				}
+			case reflect.Map:
+				elem = t.Elem()
+			}
+		}
+
+		node := &orderedNode{kind: 'o'}
+		for _, f := range fields {
+			if value, ok := v[f.name]; ok && !slices.Contains(node.keys, f.name) {
+				node.keys = append(node.keys, f.name)
+				node.values = append(node.values, p.orderedExample(value, f.typ, nil))
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if !slices.Contains(node.keys, key) {
+				node.keys = append(node.keys, key)
+				node.values = append(node.values, p.orderedExample(v[key], elem, nil))
+			}
+		}
+		return node
+	case []any:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		node := &orderedNode{kind: 'a'}
+		for _, value := range v {
+			node.values = append(node.values, p.orderedExample(value, elem, nil))
+		}
+		return node
+	}
+
+	// Other composite values, such as the zero value of a nested struct, are
+	// converted through their JSON encoding.
+	if rv := reflect.ValueOf(v); v != nil && isCompositeKind(rv.Kind()) {
+		if b, err := json.Marshal(v); err == nil {
+			if node, err := decodeOrdered(json.NewDecoder(bytes.NewReader(b))); err == nil {
+				return node
+			}
+		}
+	}
+	return &orderedNode{kind: 'v', scalar: v}
+}
+
+func isCompositeKind(k reflect.Kind) bool {
+	return k == reflect.Struct || k == reflect.Map || k == reflect.Slice || k == reflect.Array || k == reflect.Ptr
+}
+
+// exampleFieldOrder returns the JSON members of struct type t in declaration
+// order, flattening embedded structs like collectExampleFields.
+func (p *PostmanGen) exampleFieldOrder(t reflect.Type) []exampleField {
+	fields := []exampleField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && derefType(f.Type).Kind() == reflect.Struct {
+			fields = append(fields, p.exampleFieldOrder(derefType(f.Type))...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = p.untaggedName(f.Name)
+		}
+		fields = append(fields, exampleField{name: name, typ: f.Type})
+	}
+	return fields
+}
@@ -0,0 +1,101 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// bodyContentTypes are the Content-Type headers of the languages accepted by
+// the "bodyLanguage" spec key.
+var bodyContentTypes = map[string]string{
+	"json":       "application/json",
+	"text":       "text/plain",
+	"xml":        "application/xml",
+	"html":       "text/html",
+	"javascript": "application/javascript",
+	"graphql":    "application/json",
+}
+
+// graphQLBody is the body of a request in graphql mode.
+type graphQLBody struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables,omitempty"`
+}
+
+// setBodyLanguage replaces the JSON body of request with a body in language,
+// as set by the "bodyLanguage" spec key. The content comes from the "body"
+// spec key; without it, xml bodies are an XML example of t and graphql
+// variables are the JSON example body.
+func (p *PostmanGen) setBodyLanguage(request *postman.Request, spec map[string]any, language string, t reflect.Type, jsonBody string) error {
+	contentType, ok := bodyContentTypes[language]
+	if !ok {
+		return fmt.Errorf("invalid bodyLanguage %q: must be one of json, text, xml, html, javascript and graphql", language)
+	}
+	content, hasContent := spec["body"].(string)
+
+	body := &postman.Body{
+		Mode:    "raw",
+		Raw:     content,
+		Options: &postman.BodyOptions{Raw: postman.BodyOptionsRaw{Language: language}},
+	}
+	switch {
+	case language == "graphql":
+		if !hasContent {
+			return fmt.Errorf("bodyLanguage %q requires the query in the body spec key", language)
+		}
+		body = &postman.Body{Mode: "graphql", GraphQL: graphQLBody{Query: content, Variables: jsonBody}}
+	case language == "xml" && !hasContent:
+		v := reflect.New(t)
+		p.fillXMLExample(v.Elem(), map[reflect.Type]bool{})
+		b, err := xml.MarshalIndent(v.Interface(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal xml body: %w", err)
+		}
+		body.Raw = xml.Header + string(b)
+	case !hasContent:
+		return fmt.Errorf("bodyLanguage %q requires the body spec key", language)
+	}
+
+	request.Body = body
+	request.Header = slices.DeleteFunc(request.Header, func(h *postman.Header) bool {
+		return strings.EqualFold(h.Key, "Content-Type")
+	})
+	request.Header = append(request.Header, &postman.Header{Key: "Content-Type", Value: contentType})
+	return nil
+}
+
+// isJSONBody reports whether request has a raw JSON body, which negative
+// tests and boundary examples are derived from.
+func isJSONBody(request *postman.Request) bool {
+	return request.Body != nil && request.Body.Mode == "raw" &&
+		(request.Body.Options == nil || request.Body.Options.Raw.Language == "json")
+}
+
+// bodyText returns the content sent for a raw or graphql request body.
+func bodyText(body *postman.Body) (string, bool) {
+	if body == nil {
+		return "", false
+	}
+	switch body.Mode {
+	case "raw":
+		return body.Raw, true
+	case "graphql":
+		graphQL, ok := body.GraphQL.(graphQLBody)
+		if !ok {
+			return "", false
+		}
+		doc := map[string]any{"query": graphQL.Query}
+		if graphQL.Variables != "" {
+			doc["variables"] = json.RawMessage(graphQL.Variables)
+		}
+		b, err := json.Marshal(doc)
+		return string(b), err == nil
+	}
+	return "", false
+}
@@ -0,0 +1,100 @@
+package postmangen
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// SetBoundaryExamples enables boundary value examples for every registered
+// route. For each JSON body field with min, max or len constraints in its
+// `validate` tag, saved examples are attached to the request with the field
+// set to its limits and just past them. Routes can override it with the
+// "boundaryExamples" spec key.
+func (p *PostmanGen) SetBoundaryExamples(enabled bool) *PostmanGen {
+	p.boundaryExamples = enabled
+	return p
+}
+
+// boundaryCase is a JSON body with a single field set to a boundary value.
+type boundaryCase struct {
+	name  string
+	key   string
+	value any
+}
+
+func boundaryCases(fields []jsonBodyField) []boundaryCase {
+	cases := []boundaryCase{}
+
+	add := func(name string, key string, value any, ok bool) {
+		if ok {
+			cases = append(cases, boundaryCase{name: name, key: key, value: value})
+		}
+	}
+
+	for _, f := range fields {
+		rules := fieldValidateRules(f.field)
+
+		t := f.field.Type
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		step := sizeStep(t)
+
+		if rules.Len != nil {
+			value, ok := valueOfSize(t, *rules.Len)
+			add(fmt.Sprintf("%s at len (%v)", f.key, *rules.Len), f.key, value, ok)
+			value, ok = valueOfSize(t, *rules.Len+step)
+			add(fmt.Sprintf("%s just over len", f.key), f.key, value, ok)
+		}
+		if rules.Min != nil {
+			limit := *rules.Min
+			if rules.MinExclusive {
+				limit += step
+			}
+			value, ok := valueOfSize(t, limit)
+			add(fmt.Sprintf("%s at min (%v)", f.key, limit), f.key, value, ok)
+			value, ok = outOfRangeValue(t, *rules.Min, rules.MinExclusive, -1)
+			add(fmt.Sprintf("%s just under min", f.key), f.key, value, ok)
+		}
+		if rules.Max != nil {
+			limit := *rules.Max
+			if rules.MaxExclusive {
+				limit -= step
+			}
+			value, ok := valueOfSize(t, limit)
+			add(fmt.Sprintf("%s at max (%v)", f.key, limit), f.key, value, ok)
+			value, ok = outOfRangeValue(t, *rules.Max, rules.MaxExclusive, 1)
+			add(fmt.Sprintf("%s just over max", f.key), f.key, value, ok)
+		}
+	}
+
+	return cases
+}
+
+// boundaryResponses builds a saved example per boundary case, each holding a
+// copy of request with the modified JSON body.
+func (p *PostmanGen) boundaryResponses(request *postman.Request, params map[string]any, unquotedRefs []string, fields []jsonBodyField) ([]*postman.Response, error) {
+	examples := []*postman.Response{}
+
+	for _, c := range boundaryCases(fields) {
+		body := make(map[string]any, len(params))
+		for k, v := range params {
+			body[k] = v
+		}
+		body[c.key] = c.value
+
+		raw, err := p.marshalJSONBody(body, unquotedRefs, fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal json body: %w", err)
+		}
+
+		examples = append(examples, &postman.Response{
+			Name:            "boundary: " + c.name,
+			OriginalRequest: copyRequestWithBody(request, raw),
+		})
+	}
+
+	return examples, nil
+}
@@ -0,0 +1,185 @@
+package postmangen
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+)
+
+// RouteBuilder builds a RouteSpec with chained calls, started by Route and
+// registered by Add.
+type RouteBuilder struct {
+	p    *PostmanGen
+	spec RouteSpec
+}
+
+// Route starts building the route method path, e.g.
+// pg.Route("POST", "/users").Name("Create User").Input(CreateUserRequest{}).
+// Response(201, UserResponse{}).Add(). Routes without Input have an empty
+// input.
+func (p *PostmanGen) Route(method string, path string) *RouteBuilder {
+	return &RouteBuilder{
+		p: p,
+		spec: RouteSpec{
+			"method":    method,
+			"path":      path,
+			"inputType": reflect.TypeOf(struct{}{}),
+		},
+	}
+}
+
+// Name sets the item name, which defaults to the last path segment.
+func (b *RouteBuilder) Name(name string) *RouteBuilder {
+	return b.Set("name", name)
+}
+
+// Description sets the item description.
+func (b *RouteBuilder) Description(description string) *RouteBuilder {
+	return b.Set("description", description)
+}
+
+// Input sets the input type from a value or a reflect.Type.
+func (b *RouteBuilder) Input(input any) *RouteBuilder {
+	return b.Set("inputType", typeOf(input))
+}
+
+// Response declares the response returned with status, from a value or a
+// reflect.Type. It can be called once per status; a nil response has no body.
+func (b *RouteBuilder) Response(status int, response any) *RouteBuilder {
+	responses, _ := b.spec["responses"].(map[int]any)
+	if responses == nil {
+		responses = map[int]any{}
+	}
+	responses[status] = response
+	return b.Set("responses", responses)
+}
+
+// ResponseHeader adds a header to the saved response of status, e.g.
+// ResponseHeader(201, "Location", "/users/1").
+func (b *RouteBuilder) ResponseHeader(status int, key string, value string) *RouteBuilder {
+	headers, _ := b.spec["responseHeaders"].(map[int]map[string]string)
+	if headers == nil {
+		headers = map[int]map[string]string{}
+	}
+	if headers[status] == nil {
+		headers[status] = map[string]string{}
+	}
+	headers[status][key] = value
+	return b.Set("responseHeaders", headers)
+}
+
+// ResponseCookie documents a cookie set by the response of status.
+func (b *RouteBuilder) ResponseCookie(status int, cookie *http.Cookie) *RouteBuilder {
+	cookies, _ := b.spec["responseCookies"].(map[int][]*http.Cookie)
+	if cookies == nil {
+		cookies = map[int][]*http.Cookie{}
+	}
+	cookies[status] = append(cookies[status], cookie)
+	return b.Set("responseCookies", cookies)
+}
+
+// Produces declares the content types the route returns depending on the
+// Accept header, e.g. Produces("application/json", "text/csv").
+func (b *RouteBuilder) Produces(contentTypes ...string) *RouteBuilder {
+	return b.Set("produces", contentTypes)
+}
+
+// Header adds a request header.
+func (b *RouteBuilder) Header(key string, value string) *RouteBuilder {
+	headers, _ := b.spec["headers"].(map[string]string)
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headers[key] = value
+	return b.Set("headers", headers)
+}
+
+// Query adds a fixed query parameter the input does not declare, e.g.
+// Query("api-version", "2023-01").
+func (b *RouteBuilder) Query(key string, value string) *RouteBuilder {
+	query, _ := b.spec["query"].(map[string]string)
+	if query == nil {
+		query = map[string]string{}
+	}
+	query[key] = value
+	return b.Set("query", query)
+}
+
+// TestScriptFile appends the script file name to the test script of the
+// route, see WithScriptFS.
+func (b *RouteBuilder) TestScriptFile(name string) *RouteBuilder {
+	existing := specScriptFiles(b.spec, "testScriptFile")
+	return b.Set("testScriptFile", append(slices.Clip(existing), name))
+}
+
+// PreRequestScriptFile sets the script file name as the prerequest script of
+// the route, see WithScriptFS.
+func (b *RouteBuilder) PreRequestScriptFile(name string) *RouteBuilder {
+	return b.Set("preRequestScriptFile", name)
+}
+
+// TestTemplates appends template scripts to the test script of the route,
+// e.g. TestTemplates(AssertStatus(201), ExtractField("id", "user_id")).
+func (b *RouteBuilder) TestTemplates(scripts ...TemplateScript) *RouteBuilder {
+	existing, _ := b.spec["testTemplates"].([]TemplateScript)
+	return b.Set("testTemplates", append(existing, scripts...))
+}
+
+// Extract stores the member of the JSON response at path, e.g. "data.id", in
+// the collection variable for the requests that follow.
+func (b *RouteBuilder) Extract(variable string, path string) *RouteBuilder {
+	extract, _ := b.spec["extract"].(map[string]string)
+	if extract == nil {
+		extract = map[string]string{}
+	}
+	extract[variable] = path
+	return b.Set("extract", extract)
+}
+
+// DependsOn appends prerequisite requests the route's pre-request script
+// sends before the request, see Dependency.
+func (b *RouteBuilder) DependsOn(dependencies ...Dependency) *RouteBuilder {
+	existing := specDependencies(b.spec)
+	return b.Set("dependsOn", append(slices.Clip(existing), dependencies...))
+}
+
+// CommonQuery adds the query parameter groups added with WithCommonQuery
+// under names to the route.
+func (b *RouteBuilder) CommonQuery(names ...string) *RouteBuilder {
+	existing := specCommonQuery(b.spec)
+	return b.Set("commonQuery", append(slices.Clip(existing), names...))
+}
+
+// Example overrides the example of the fields named key for this route.
+func (b *RouteBuilder) Example(key string, value any) *RouteBuilder {
+	examples, _ := b.spec["examples"].(map[string]any)
+	if examples == nil {
+		examples = map[string]any{}
+	}
+	examples[key] = value
+	return b.Set("examples", examples)
+}
+
+// Set sets any other spec key, such as "maxResponseTimeMs" or "sse".
+func (b *RouteBuilder) Set(key string, value any) *RouteBuilder {
+	b.spec[key] = value
+	return b
+}
+
+// Spec returns the built spec, e.g. for RegisterAll.
+func (b *RouteBuilder) Spec() RouteSpec {
+	return b.spec
+}
+
+// Add registers the route.
+func (b *RouteBuilder) Add() error {
+	return b.p.Register(b.spec)
+}
+
+// typeOf returns v if it is a reflect.Type and the type of v otherwise.
+func typeOf(v any) reflect.Type {
+	if t, ok := v.(reflect.Type); ok {
+		return t
+	}
+	return reflect.TypeOf(v)
+}
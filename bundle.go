@@ -0,0 +1,93 @@
+package postmangen
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// WriteBundle writes a zip archive with everything needed to use the
+// collection, for release pipelines:
+//
+//   - collection.json, the collection written by Write
+//   - environment.json, the environment written by WriteEnvironment
+//   - data.csv and data.json, the iteration data files, if SetDataDriven is
+//     enabled
+//   - certificates.json, the Newman certificate list, if client certificates
+//     were added
+//   - README.md, listing the routes and how to run the collection
+//
+// The write options apply to the collection and the environment.
+func (p *PostmanGen) WriteBundle(path string, opts ...WriteOption) error {
+	type bundleFile struct {
+		name  string
+		write func(w io.Writer) error
+	}
+	files := []bundleFile{
+		{"collection.json", func(w io.Writer) error { return p.Write(w, opts...) }},
+		{"environment.json", func(w io.Writer) error { return p.WriteEnvironment(w, opts...) }},
+	}
+	if p.dataDriven {
+		files = append(files,
+			bundleFile{"data.csv", p.WriteDataCSV},
+			bundleFile{"data.json", p.WriteDataJSON},
+		)
+	}
+	if len(p.clientCertificates) > 0 {
+		files = append(files, bundleFile{"certificates.json", p.WriteCertificateList})
+	}
+	files = append(files, bundleFile{"README.md", p.writeBundleReadme})
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if err := file.write(w); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// writeBundleReadme writes the README.md of a bundle.
+func (p *PostmanGen) writeBundleReadme(w io.Writer) error {
+	b := strings.Builder{}
+	b.WriteString("# " + p.collection.Info.Name + "\n\n")
+	if p.collection.Info.Description.Content != "" {
+		b.WriteString(p.collection.Info.Description.Content + "\n\n")
+	}
+
+	b.WriteString("Import `collection.json` and `environment.json` into Postman, or run them with Newman:\n\n")
+	b.WriteString("```sh\nnewman run collection.json -e environment.json")
+	if p.dataDriven {
+		b.WriteString(" -d data.csv")
+	}
+	if len(p.clientCertificates) > 0 {
+		b.WriteString(" --ssl-client-cert-list certificates.json")
+	}
+	b.WriteString("\n```\n")
+
+	if len(p.routes) > 0 {
+		b.WriteString("\n## Routes\n\n")
+		for _, r := range p.routes {
+			b.WriteString(fmt.Sprintf("- `%s %s`", strings.ToUpper(r.method), r.path))
+			if r.description != "" {
+				b.WriteString(": " + strings.SplitN(r.description, "\n", 2)[0])
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
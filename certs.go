@@ -0,0 +1,125 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// ClientCertificate describes the client certificate an mTLS-protected host
+// requires. Paths and the passphrase may reference collection variables, e.g.
+// "{{cert_dir}}/client.pem".
+type ClientCertificate struct {
+	Name string
+	// Matches are the URL patterns the certificate is used for, in Postman's
+	// format, e.g. "https://api.example.com:8443/*".
+	Matches  []string
+	CertPath string
+	KeyPath  string
+	PFXPath  string
+	// Passphrase is never written into the collection. Reference a variable,
+	// e.g. "{{cert_passphrase}}", to have the description name it.
+	Passphrase string
+}
+
+// AddClientCertificate documents a client certificate. The collection format
+// cannot carry certificates, so they are listed in a "Client certificates"
+// section of the collection description, and WriteCertificateList exports
+// them for Newman.
+func (p *PostmanGen) AddClientCertificate(cert ClientCertificate) *PostmanGen {
+	p.clientCertificates = append(p.clientCertificates, cert)
+	return p
+}
+
+// certificateNotes returns the description section listing the client
+// certificates, or an empty string if there are none.
+func (p *PostmanGen) certificateNotes() string {
+	if len(p.clientCertificates) == 0 {
+		return ""
+	}
+
+	b := strings.Builder{}
+	b.WriteString("\n\n## Client certificates\n\n")
+	b.WriteString("These hosts require a client certificate (mTLS). Add it in Postman under Settings > Certificates:\n")
+	for _, cert := range p.clientCertificates {
+		files := []string{}
+		if cert.CertPath != "" {
+			files = append(files, "certificate `"+cert.CertPath+"`")
+		}
+		if cert.KeyPath != "" {
+			files = append(files, "key `"+cert.KeyPath+"`")
+		}
+		if cert.PFXPath != "" {
+			files = append(files, "PFX file `"+cert.PFXPath+"`")
+		}
+		if cert.Passphrase != "" {
+			files = append(files, certificatePassphraseNote(cert.Passphrase))
+		}
+
+		b.WriteString("\n- ")
+		if cert.Name != "" {
+			b.WriteString(cert.Name + ": ")
+		}
+		b.WriteString("`" + strings.Join(cert.Matches, "`, `") + "`")
+		if len(files) > 0 {
+			b.WriteString(" with " + strings.Join(files, ", "))
+		}
+	}
+	return b.String()
+}
+
+// certificatePassphraseNote describes the passphrase of a client certificate without
+// revealing it: only a passphrase that is a single variable reference, e.g.
+// "{{cert_passphrase}}", is named.
+func certificatePassphraseNote(passphrase string) string {
+	if name, ok := strings.CutPrefix(passphrase, "{{"); ok {
+		if name, ok := strings.CutSuffix(name, "}}"); ok && name != "" && !strings.ContainsAny(name, "{}") {
+			return "the passphrase in the `{{" + name + "}}` variable"
+		}
+	}
+	return "a passphrase"
+}
+
+type certificateFile struct {
+	Src string `json:"src"`
+}
+
+type certificateEntry struct {
+	Name       string           `json:"name,omitempty"`
+	Matches    []string         `json:"matches"`
+	Cert       *certificateFile `json:"cert,omitempty"`
+	Key        *certificateFile `json:"key,omitempty"`
+	PFX        *certificateFile `json:"pfx,omitempty"`
+	Passphrase string           `json:"passphrase,omitempty"`
+}
+
+// WriteCertificateList writes the client certificates in the format of
+// Newman's --ssl-client-cert-list option. References to collection variables
+// are substituted with their values.
+func (p *PostmanGen) WriteCertificateList(w io.Writer) error {
+	file := func(path string) *certificateFile {
+		if path == "" {
+			return nil
+		}
+		return &certificateFile{Src: p.resolveVariables(path)}
+	}
+
+	entries := []certificateEntry{}
+	for _, cert := range p.clientCertificates {
+		entries = append(entries, certificateEntry{
+			Name:       cert.Name,
+			Matches:    cert.Matches,
+			Cert:       file(cert.CertPath),
+			Key:        file(cert.KeyPath),
+			PFX:        file(cert.PFXPath),
+			Passphrase: p.resolveVariables(cert.Passphrase),
+		})
+	}
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,321 @@
+package postmangen
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// collectionSchemaJSON is the Postman collection v2.1.0 JSON schema, as
+// published at https://schema.getpostman.com/json/collection/v2.1.0/collection.json.
+//
+//go:embed schemas/collection-v2.1.0.json
+var collectionSchemaJSON []byte
+
+// collectionSchema decodes collectionSchemaJSON once.
+var collectionSchema = sync.OnceValues(func() (map[string]any, error) {
+	var schema map[string]any
+	if err := json.Unmarshal(collectionSchemaJSON, &schema); err != nil {
+		return nil, fmt.Errorf("decoding collection schema: %w", err)
+	}
+	return schema, nil
+})
+
+// Validate checks the generated collection against the Postman collection
+// v2.1.0 schema and returns an error listing every violation with its
+// location, e.g. `item[0].request.body.mode: must be one of ...`, so problems
+// surface before Postman rejects the import.
+//
+// The official schema is embedded in the package, and checked with the
+// draft-07 keywords it uses: $ref, type, enum, const, properties, required,
+// items, oneOf, anyOf, allOf, minimum, maximum, minLength and maxLength.
+func (p *PostmanGen) Validate() error {
+	data, err := p.render()
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("collection is not valid JSON: %w", err)
+	}
+
+	schema, err := collectionSchema()
+	if err != nil {
+		return err
+	}
+	definitions, _ := schema["definitions"].(map[string]any)
+	v := &schemaValidator{definitions: definitions}
+
+	errs := []error{}
+	for _, violation := range v.validate("", schema, doc) {
+		errs = append(errs, violation)
+	}
+	return errors.Join(errs...)
+}
+
+// schemaViolation is a value at path not matching its schema.
+type schemaViolation struct {
+	path    string
+	message string
+}
+
+func (e schemaViolation) Error() string {
+	path := e.path
+	if path == "" {
+		path = "$"
+	}
+	return path + ": " + e.message
+}
+
+// schemaValidator checks JSON values decoded into any against a JSON schema.
+type schemaValidator struct {
+	definitions map[string]any
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+// validate returns the violations of value, at path, against schema.
+func (v *schemaValidator) validate(path string, schema map[string]any, value any) []schemaViolation {
+	if ref, ok := schema["$ref"].(string); ok {
+		// In draft-07 the keywords next to $ref are ignored.
+		target, err := v.resolve(ref)
+		if err != nil {
+			return []schemaViolation{{path, err.Error()}}
+		}
+		return v.validate(path, target, value)
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !slices.ContainsFunc(types, func(typ string) bool {
+		return hasJSONType(value, typ)
+	}) {
+		return []schemaViolation{{path, "must be " + typeNames(types)}}
+	}
+
+	violations := []schemaViolation{}
+	fail := func(path string, format string, args ...any) {
+		violations = append(violations, schemaViolation{path, fmt.Sprintf(format, args...)})
+	}
+
+	if allowed, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(allowed, func(a any) bool {
+		return jsonEqual(a, value)
+	}) {
+		names := make([]string, len(allowed))
+		for i, a := range allowed {
+			names[i] = jsonText(a)
+		}
+		fail(path, "must be one of %s, got %s", strings.Join(names, ", "), jsonText(value))
+	}
+	if want, ok := schema["const"]; ok && !jsonEqual(want, value) {
+		fail(path, "must be %s, got %s", jsonText(want), jsonText(value))
+	}
+
+	switch value := value.(type) {
+	case string:
+		length := float64(utf8.RuneCountInString(value))
+		if limit, ok := schema["minLength"].(float64); ok && length < limit {
+			fail(path, "must be at least %v characters long", limit)
+		}
+		if limit, ok := schema["maxLength"].(float64); ok && length > limit {
+			fail(path, "must be at most %v characters long", limit)
+		}
+	case float64:
+		if limit, ok := schema["minimum"].(float64); ok && value < limit {
+			fail(path, "must be at least %v", limit)
+		}
+		if limit, ok := schema["maximum"].(float64); ok && value > limit {
+			fail(path, "must be at most %v", limit)
+		}
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, key := range required {
+				if key, ok := key.(string); ok {
+					if _, ok := value[key]; !ok {
+						fail(joinPath(path, key), "is required")
+					}
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			for _, key := range slices.Sorted(maps.Keys(properties)) {
+				property, ok := value[key]
+				propertySchema, isSchema := properties[key].(map[string]any)
+				if ok && isSchema {
+					violations = append(violations, v.validate(joinPath(path, key), propertySchema, property)...)
+				}
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				violations = append(violations, v.validate(indexPath(path, i), items, item)...)
+			}
+		}
+	}
+
+	if branches, ok := schema["allOf"].([]any); ok {
+		for _, branch := range branches {
+			if branch, ok := branch.(map[string]any); ok {
+				violations = append(violations, v.validate(path, branch, value)...)
+			}
+		}
+	}
+	if branches, ok := schema["anyOf"].([]any); ok {
+		if matched, closest := v.matchBranches(path, branches, value); matched == 0 {
+			violations = append(violations, closest...)
+		}
+	}
+	if branches, ok := schema["oneOf"].([]any); ok {
+		switch matched, closest := v.matchBranches(path, branches, value); {
+		case matched == 0:
+			violations = append(violations, closest...)
+		case matched > 1:
+			fail(path, "must match exactly one schema, matches %d", matched)
+		}
+	}
+	return violations
+}
+
+// matchBranches returns how many of branches value matches, and when it
+// matches none the violations of the closest branch, which is usually the
+// one the value was meant to match: the branch with the fewest violations
+// about value itself, then the one failing deepest in value.
+func (v *schemaValidator) matchBranches(path string, branches []any, value any) (int, []schemaViolation) {
+	matched := 0
+	var closest []schemaViolation
+	closestOwn, closestDepth := 0, 0
+	for _, branch := range branches {
+		branch, ok := branch.(map[string]any)
+		if !ok {
+			continue
+		}
+		violations := v.validate(path, branch, value)
+		if len(violations) == 0 {
+			matched++
+			continue
+		}
+		own, depth := 0, 0
+		for _, violation := range violations {
+			if violation.about(path) {
+				own++
+			}
+			depth = max(depth, len(violation.path))
+		}
+		if closest == nil || own < closestOwn || own == closestOwn && depth > closestDepth {
+			closest, closestOwn, closestDepth = violations, own, depth
+		}
+	}
+	return matched, closest
+}
+
+// about reports whether e is about the value at path itself, its type, its
+// value or a missing member, rather than about a value inside it.
+func (e schemaViolation) about(path string) bool {
+	if e.path == path {
+		return true
+	}
+	member, ok := strings.CutPrefix(e.path, joinPath(path, ""))
+	return ok && e.message == "is required" && !strings.ContainsAny(member, ".[")
+}
+
+// resolve returns the schema ref points to, a "#/definitions/..." reference.
+func (v *schemaValidator) resolve(ref string) (map[string]any, error) {
+	name, ok := strings.CutPrefix(ref, "#/definitions/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema reference %q", ref)
+	}
+	schema, ok := v.definitions[name].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unknown schema reference %q", ref)
+	}
+	return schema, nil
+}
+
+// schemaTypes returns the types allowed by the type keyword of schema.
+func schemaTypes(schema map[string]any) []string {
+	switch typ := schema["type"].(type) {
+	case string:
+		return []string{typ}
+	case []any:
+		types := []string{}
+		for _, t := range typ {
+			if t, ok := t.(string); ok {
+				types = append(types, t)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// hasJSONType reports whether value, decoded by encoding/json, has the JSON
+// schema type typ.
+func hasJSONType(value any, typ string) bool {
+	switch value := value.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case string:
+		return typ == "string"
+	case float64:
+		return typ == "number" || typ == "integer" && value == math.Trunc(value)
+	case []any:
+		return typ == "array"
+	case map[string]any:
+		return typ == "object"
+	}
+	return false
+}
+
+// typeNames describes types, e.g. "an object or null".
+func typeNames(types []string) string {
+	names := make([]string, len(types))
+	for i, typ := range types {
+		switch typ {
+		case "null":
+			names[i] = "null"
+		case "object", "array", "integer":
+			names[i] = "an " + typ
+		default:
+			names[i] = "a " + typ
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+// jsonEqual reports whether a and b, decoded by encoding/json, are the same
+// JSON value.
+func jsonEqual(a any, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// jsonText returns value as JSON text, strings unquoted.
+func jsonText(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
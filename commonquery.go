@@ -0,0 +1,98 @@
+package postmangen
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// WithCommonQuery adds query parameter groups, structs whose fields have
+// query tags such as pagination or sorting parameters, which routes
+// reference by type name with the "commonQuery" spec key instead of
+// declaring the parameters in every input, e.g.
+//
+//	pg := postmangen.NewPostmanGen("My API", "", postmangen.WithCommonQuery(Pagination{}, Sorting{}))
+//	pg.Register(postmangen.RouteSpec{
+//		"method":      "GET",
+//		"path":        "/users",
+//		"inputType":   reflect.TypeOf(ListUsersRequest{}),
+//		"commonQuery": []string{"Pagination", "Sorting"},
+//	})
+//
+// Parameters the input already declares are left out.
+func WithCommonQuery(queries ...any) Option {
+	return func(p *PostmanGen) {
+		for _, query := range queries {
+			t := derefType(reflect.TypeOf(query))
+			p.commonQueries[t.Name()] = t
+		}
+	}
+}
+
+// specCommonQuery returns the group names of the "commonQuery" spec key, a
+// string or a []string.
+func specCommonQuery(spec map[string]any) []string {
+	switch v := spec["commonQuery"].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// withCommonQuery returns a struct type with the fields of inputType and the
+// query fields of the named groups it does not declare. Groups without
+// declared parameters are kept whole, so their doc comments still describe
+// their fields.
+func (p *PostmanGen) withCommonQuery(inputType reflect.Type, names []string) (reflect.Type, error) {
+	declared := map[string]bool{}
+	walkStructFields(inputType, func(field reflect.StructField) {
+		if key := field.Tag.Get("query"); key != "" && key != "-" {
+			declared[key] = true
+		}
+	})
+
+	// The input is kept as an untagged field, which Register walks into like
+	// any nested struct.
+	fields := []reflect.StructField{{Name: "Input", Type: inputType}}
+	used := map[string]bool{"Input": true}
+	add := func(f reflect.StructField) {
+		name := f.Name
+		for i := 2; used[f.Name]; i++ {
+			f.Name = name + strconv.Itoa(i)
+		}
+		used[f.Name] = true
+		f.Index, f.Offset, f.Anonymous = nil, 0, false
+		fields = append(fields, f)
+	}
+
+	for _, name := range names {
+		group, ok := p.commonQueries[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown common query %q: add it with WithCommonQuery", name)
+		}
+		params := []reflect.StructField{}
+		whole := true
+		walkStructFields(group, func(field reflect.StructField) {
+			key := field.Tag.Get("query")
+			if key == "" || key == "-" {
+				return
+			}
+			if declared[key] {
+				whole = false
+				return
+			}
+			declared[key] = true
+			params = append(params, field)
+		})
+		if whole {
+			add(reflect.StructField{Name: name, Type: group})
+			continue
+		}
+		for _, param := range params {
+			add(param)
+		}
+	}
+	return reflect.StructOf(fields), nil
+}
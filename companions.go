@@ -0,0 +1,88 @@
+package postmangen
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// originVariable holds the Origin sent with CORS preflight requests.
+const originVariable = "origin"
+
+// SetHeadAndOptions adds HEAD and OPTIONS siblings to every GET route, with
+// the same URL and path variables. The OPTIONS request is a CORS preflight
+// from the {{origin}} variable, tested for allowing GET. Routes can override
+// it with the "headAndOptions" spec key.
+func (p *PostmanGen) SetHeadAndOptions(enabled bool) *PostmanGen {
+	p.headAndOptions = enabled
+	return p
+}
+
+// companionItems returns the HEAD and OPTIONS siblings of the GET request.
+func (p *PostmanGen) companionItems(name string, request *postman.Request) []*postman.Items {
+	head := companionRequest(request, http.MethodHead)
+	head.Header = append(head.Header, request.Header...)
+
+	preflight := companionRequest(request, http.MethodOptions)
+	preflight.Header = []*postman.Header{
+		{Key: "Origin", Value: "{{" + originVariable + "}}"},
+		{Key: "Access-Control-Request-Method", Value: http.MethodGet},
+	}
+	// Browsers send preflight requests without credentials.
+	preflight.Auth = postman.CreateAuth(postman.NoAuth)
+	p.addPathParamVariable(originVariable, "https://example.com", "Origin of the CORS preflight requests.")
+
+	return []*postman.Items{
+		postman.CreateItem(postman.Item{
+			Name:      name + " (HEAD)",
+			Request:   head,
+			Responses: []*postman.Response{},
+			Events:    appendScript(nil, postman.Test, headTestScript()...),
+		}),
+		postman.CreateItem(postman.Item{
+			Name:      name + " (OPTIONS)",
+			Request:   preflight,
+			Responses: []*postman.Response{},
+			Events:    appendScript(nil, postman.Test, preflightTestScript()...),
+		}),
+	}
+}
+
+// companionRequest returns a copy of request sent with method and no body.
+func companionRequest(request *postman.Request, method string) *postman.Request {
+	url := *request.URL
+	return &postman.Request{
+		URL:    &url,
+		Method: postman.Method(method),
+		Header: []*postman.Header{},
+		Auth:   request.Auth,
+	}
+}
+
+func headTestScript() []string {
+	return []string{
+		`pm.test("HEAD response has no body", function () {`,
+		`    pm.expect(pm.response.text()).to.be.empty;`,
+		`});`,
+	}
+}
+
+func preflightTestScript() []string {
+	return []string{
+		`pm.test("CORS preflight allows GET", function () {`,
+		`    pm.response.to.have.header("Access-Control-Allow-Origin");`,
+		`    pm.expect(pm.response.headers.get("Access-Control-Allow-Methods")).to.include("GET");`,
+		`});`,
+	}
+}
+
+// wantsCompanions reports whether the HEAD and OPTIONS siblings are added for
+// a route.
+func (p *PostmanGen) wantsCompanions(spec map[string]any, method string) bool {
+	enabled := p.headAndOptions
+	if v, ok := spec["headAndOptions"].(bool); ok {
+		enabled = v
+	}
+	return enabled && strings.EqualFold(method, http.MethodGet)
+}
@@ -0,0 +1,112 @@
+package postmangen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// WithContentHashes marks each generated request item with an "x-postmangen"
+// member holding its endpoint, the hash of its route's types and the hash of
+// its content, as Regenerate does. Merge and diff tooling can then tell
+// generated items from requests added by hand, and EditedItems finds the
+// generated items edited since.
+func WithContentHashes() WriteOption {
+	return func(s *writeSettings) {
+		s.contentHashes = true
+	}
+}
+
+// addContentHashes marks the request items of a rendered collection, whose
+// items are items.
+func (p *PostmanGen) addContentHashes(data []byte, items []*postman.Items) ([]byte, error) {
+	doc, err := decodeOrdered(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	if nodes := memberOf(doc, "item"); nodes != nil {
+		if err := markGenerated(items, nodes, p.generatedItems(nil)); err != nil {
+			return nil, err
+		}
+	}
+	return encodeCollection(doc)
+}
+
+// contentHash returns the hash of an item node without its "x-postmangen"
+// member. The hash is computed on a canonical form, with sorted keys and
+// without the fields Postman rewrites on import and export, so it survives a
+// round trip through Postman.
+func contentHash(item *orderedNode) (string, error) {
+	content := &orderedNode{kind: 'o'}
+	for i, key := range item.keys {
+		if key != regenerateKey {
+			content.keys = append(content.keys, key)
+			content.values = append(content.values, item.values[i])
+		}
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := writeOrderedJSON(buf, content); err != nil {
+		return "", err
+	}
+	var v any
+	if err := json.Unmarshal(buf.Bytes(), &v); err != nil {
+		return "", fmt.Errorf("failed to hash item: %w", err)
+	}
+	canonical, err := json.Marshal(stripVolatile(v))
+	if err != nil {
+		return "", fmt.Errorf("failed to hash item: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// EditedItems returns the generated items of a collection written with
+// WithContentHashes or by Regenerate whose content no longer matches their
+// hash, i.e. that were edited after they were generated. Items are written as
+// "POST /users: Create User".
+func EditedItems(collection io.Reader) ([]string, error) {
+	doc, err := decodeOrdered(json.NewDecoder(collection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+	return editedItems(memberOf(doc, "item"), []string{})
+}
+
+// editedItems adds the edited items of an item array to edited.
+func editedItems(items *orderedNode, edited []string) ([]string, error) {
+	if items == nil || items.kind != 'a' {
+		return edited, nil
+	}
+	for _, item := range items.values {
+		if children := memberOf(item, "item"); children != nil && memberOf(item, "request") == nil {
+			var err error
+			if edited, err = editedItems(children, edited); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		marker := memberOf(item, regenerateKey)
+		hash, ok := scalarString(memberOf(marker, "contentHash"))
+		if !ok {
+			continue
+		}
+		current, err := contentHash(item)
+		if err != nil {
+			return nil, err
+		}
+		if hash == current {
+			continue
+		}
+		endpoint, _ := scalarString(memberOf(marker, "endpoint"))
+		name, _ := scalarString(memberOf(item, "name"))
+		edited = append(edited, endpoint+": "+name)
+	}
+	return edited, nil
+}
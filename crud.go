@@ -0,0 +1,175 @@
+package postmangen
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// CRUDOption configures the routes registered by RegisterCRUD.
+type CRUDOption func(*crudSettings)
+
+type crudSettings struct {
+	idParam      string
+	singular     string
+	plural       string
+	updateMethod string
+}
+
+// WithIDParam sets the name of the path parameter identifying a resource,
+// "id" by default.
+func WithIDParam(name string) CRUDOption {
+	return func(s *crudSettings) {
+		s.idParam = name
+	}
+}
+
+// WithResourceName sets the singular and plural names used in the route
+// names, which are derived from the last path segment by default, e.g. "User"
+// and "Users" for /users.
+func WithResourceName(singular string, plural string) CRUDOption {
+	return func(s *crudSettings) {
+		s.singular = singular
+		s.plural = plural
+	}
+}
+
+// WithUpdateMethod sets the method of the update route, PUT by default.
+func WithUpdateMethod(method string) CRUDOption {
+	return func(s *crudSettings) {
+		s.updateMethod = method
+	}
+}
+
+// RegisterCRUD registers the five standard routes of the resource collection
+// at path, e.g. for "/users":
+//
+//	GET    /users      List Users   200 []resource
+//	GET    /users/:id  Get User     200 resource
+//	POST   /users      Create User  201 resource
+//	PUT    /users/:id  Update User  200 resource
+//	DELETE /users/:id  Delete User  204
+//
+// The create, update and resource types are given as values or reflect.Type;
+// a nil create or update input skips that route. Inputs that do not declare
+// the id path parameter get it with the example "1". The routes are grouped in
+// the folder of path. Failures are handled like in RegisterAll.
+func (p *PostmanGen) RegisterCRUD(path string, create any, update any, resource any, opts ...CRUDOption) error {
+	path = "/" + strings.Trim(path, "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	plural := crudTitle(segments[len(segments)-1])
+
+	settings := crudSettings{
+		idParam:      "id",
+		singular:     singularName(plural),
+		plural:       plural,
+		updateMethod: http.MethodPut,
+	}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	if resource == nil {
+		return errors.New("invalid resource type: must not be nil")
+	}
+	resourceType := typeOf(resource)
+	itemPath := path + "/:" + settings.idParam
+	empty := reflect.TypeOf(struct{}{})
+
+	specs := []RouteSpec{
+		{
+			"method":       http.MethodGet,
+			"path":         path,
+			"name":         "List " + settings.plural,
+			"inputType":    empty,
+			"responseType": reflect.SliceOf(resourceType),
+		},
+		{
+			"method":       http.MethodGet,
+			"path":         itemPath,
+			"name":         "Get " + settings.singular,
+			"inputType":    withIDParam(empty, settings.idParam),
+			"responseType": resourceType,
+		},
+	}
+	if create != nil {
+		specs = append(specs, RouteSpec{
+			"method":         http.MethodPost,
+			"path":           path,
+			"name":           "Create " + settings.singular,
+			"inputType":      typeOf(create),
+			"responseType":   resourceType,
+			"responseStatus": http.StatusCreated,
+		})
+	}
+	if update != nil {
+		specs = append(specs, RouteSpec{
+			"method":       settings.updateMethod,
+			"path":         itemPath,
+			"name":         "Update " + settings.singular,
+			"inputType":    withIDParam(typeOf(update), settings.idParam),
+			"responseType": resourceType,
+		})
+	}
+	specs = append(specs, RouteSpec{
+		"method":    http.MethodDelete,
+		"path":      itemPath,
+		"name":      "Delete " + settings.singular,
+		"inputType": withIDParam(empty, settings.idParam),
+		"responses": map[int]reflect.Type{http.StatusNoContent: nil},
+	})
+
+	errs := []error{}
+	for _, spec := range specs {
+		// The list and create routes would otherwise sit next to the folder
+		// of the routes with the id parameter.
+		spec["folder"] = strings.Join(segments, "/")
+		if err := p.Register(spec); err != nil {
+			errs = append(errs, fmt.Errorf("%v %v: %w", spec["method"], spec["path"], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// withIDParam returns t, or a struct embedding it with the path parameter key
+// if t does not declare it.
+func withIDParam(t reflect.Type, key string) reflect.Type {
+	declared := false
+	walkStructFields(t, func(field reflect.StructField) {
+		if field.Tag.Get("param") == key {
+			declared = true
+		}
+	})
+	if declared || derefType(t).Kind() != reflect.Struct {
+		return t
+	}
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Input", Type: t},
+		{Name: "ID", Type: reflect.TypeOf(""), Tag: reflect.StructTag(fmt.Sprintf(`param:%q example:"1"`, key))},
+	})
+}
+
+// crudTitle turns a path segment such as "order-items" into "Order Items".
+func crudTitle(segment string) string {
+	words := strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' })
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// singularName returns the singular of an English plural such as "Users" or
+// "Categories", or name itself if it does not look plural.
+func singularName(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
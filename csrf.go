@@ -0,0 +1,68 @@
+package postmangen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CSRFToken configures the pre-request script that fetches a CSRF token and
+// sends it with mutating requests. Empty fields use the defaults noted on
+// each field.
+type CSRFToken struct {
+	// TokenURL is the endpoint returning the token and may reference
+	// variables, e.g. "{{base_url}}/csrf".
+	TokenURL string
+	// Header receives the token on POST, PUT, PATCH and DELETE requests,
+	// "X-CSRF-Token" by default.
+	Header string
+	// ResponseField is the member of the JSON token response holding the
+	// token, "csrf_token" by default.
+	ResponseField string
+	// ResponseHeader reads the token from this header of the token response
+	// instead of its body when set.
+	ResponseHeader string
+	// Variable caches the token, "csrf_token" by default. The token is fetched
+	// when the variable is empty.
+	Variable string
+}
+
+// WithCSRFToken adds a collection-level pre-request script that fetches a
+// CSRF token once and injects it into every mutating request.
+func WithCSRFToken(cfg CSRFToken) Option {
+	return func(p *PostmanGen) {
+		p.AddPreRequestScript(CSRFTokenScript(cfg))
+	}
+}
+
+// CSRFTokenScript returns the pre-request script added by WithCSRFToken, for
+// use in custom script setups. The script is a block, so it can be combined
+// with other scripts.
+func CSRFTokenScript(cfg CSRFToken) string {
+	header := defaultString(cfg.Header, "X-CSRF-Token")
+	variable := defaultString(cfg.Variable, "csrf_token")
+
+	readToken := fmt.Sprintf(`res.json()[%s]`, jsString(defaultString(cfg.ResponseField, "csrf_token")))
+	if cfg.ResponseHeader != "" {
+		readToken = fmt.Sprintf(`res.headers.get(%s)`, jsString(cfg.ResponseHeader))
+	}
+
+	lines := []string{
+		`// Send a CSRF token with mutating requests, fetching it when missing.`,
+		`if (["POST", "PUT", "PATCH", "DELETE"].includes(pm.request.method)) {`,
+		fmt.Sprintf(`    const setCSRFHeader = () => pm.request.headers.upsert({ key: %s, value: pm.variables.get(%s) });`, jsString(header), jsString(variable)),
+		fmt.Sprintf(`    if (pm.variables.get(%s)) {`, jsString(variable)),
+		`        setCSRFHeader();`,
+		`    } else {`,
+		fmt.Sprintf(`        pm.sendRequest({ url: pm.variables.replaceIn(%s), method: "GET" }, function (err, res) {`, jsString(cfg.TokenURL)),
+		`            if (err || res.code >= 400) {`,
+		`                console.error("CSRF token request failed", err || res.status);`,
+		`                return;`,
+		`            }`,
+		fmt.Sprintf(`            pm.collectionVariables.set(%s, %s);`, jsString(variable), readToken),
+		`            setCSRFHeader();`,
+		`        });`,
+		`    }`,
+		`}`,
+	}
+	return strings.Join(lines, "\n")
+}
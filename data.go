@@ -0,0 +1,103 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+// SetDataDriven makes registered routes reference iteration data instead of
+// embedding example values: body fields, form fields, query parameters and
+// path variables are emitted as {{key}}, where key is the field's tag name.
+// The matching data file is produced by WriteDataCSV or WriteDataJSON and can
+// be passed to the Postman collection runner or newman's --iteration-data.
+//
+// It must be called before registering the routes it should apply to.
+func (p *PostmanGen) SetDataDriven(enabled bool) *PostmanGen {
+	p.dataDriven = enabled
+	return p
+}
+
+// WriteDataCSV writes an iteration data file in CSV format. There is one
+// column per field key seen across all registered routes, and one row holding
+// the example or placeholder values used for them.
+func (p *PostmanGen) WriteDataCSV(w io.Writer) error {
+	row := make([]string, len(p.dataColumns))
+	for i, column := range p.dataColumns {
+		row[i] = p.dataRow[column]
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(p.dataColumns); err != nil {
+		return err
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteDataJSON writes the iteration data file described in WriteDataCSV in
+// JSON format, as an array with a single object keeping the column order.
+func (p *PostmanGen) WriteDataJSON(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	buf.WriteString("[\n  {")
+	for i, column := range p.dataColumns {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(p.dataRow[column])
+		if err != nil {
+			return err
+		}
+		buf.WriteString("\n    ")
+		buf.Write(key)
+		buf.WriteString(": ")
+		buf.Write(value)
+	}
+	buf.WriteString("\n  }\n]\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// recordDataValue remembers value as the data file entry for column key. The
+// first route registering a key decides its value. During Register the value
+// is kept with the route's pending changes.
+func (p *PostmanGen) recordDataValue(key string, value string) {
+	if _, ok := p.dataRow[key]; ok {
+		return
+	}
+	if changes := p.pending; changes != nil {
+		if _, ok := changes.dataRow[key]; !ok {
+			changes.dataColumns = append(changes.dataColumns, key)
+			changes.dataRow[key] = value
+		}
+		return
+	}
+	p.dataColumns = append(p.dataColumns, key)
+	p.dataRow[key] = value
+}
+
+func dataReference(key string) string {
+	return "{{" + key + "}}"
+}
+
+// jsonDataText returns the text Postman has to substitute for value inside a
+// raw JSON body, and whether the reference must stay inside quotes.
+func jsonDataText(value any) (string, bool) {
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", true
+	}
+	return string(b), false
+}
@@ -0,0 +1,129 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Dependency is a prerequisite request a route's pre-request script sends
+// with pm.sendRequest, such as fetching a signed upload URL, whose response
+// members are stored in collection variables the route's request uses.
+type Dependency struct {
+	// Method is the method of the request, "GET" by default.
+	Method string
+	// Path is appended to the route's base URL, e.g. "/uploads/sign", and may
+	// reference variables. Absolute URLs and URLs starting with a variable,
+	// e.g. "{{auth_url}}/token", are used as they are and are not sent the
+	// collection's bearer token.
+	Path string
+	// Body is sent as JSON when not nil. Variable references in it are
+	// resolved.
+	Body any
+	// Extract maps collection variables to paths in the JSON response, e.g.
+	// {"upload_url": "data.url"}.
+	Extract map[string]string
+}
+
+// specDependencies returns the dependencies of the "dependsOn" spec key, a
+// Dependency or a []Dependency.
+func specDependencies(spec map[string]any) []Dependency {
+	switch v := spec["dependsOn"].(type) {
+	case Dependency:
+		return []Dependency{v}
+	case []Dependency:
+		return v
+	}
+	return nil
+}
+
+// dependencyScript returns the pre-request script sending the dependencies
+// of a route whose base URL variable is baseVariable. Dependencies are sent
+// one after another, so each can use the variables extracted by the previous
+// ones.
+func (p *PostmanGen) dependencyScript(dependencies []Dependency, baseVariable string) ([]string, error) {
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+
+	headers := []string{}
+	if token, ok := p.bearerTokenVariable(); ok {
+		headers = append(headers, fmt.Sprintf(`"Authorization": pm.variables.replaceIn(%s)`, jsString("Bearer {{"+token+"}}")))
+	}
+
+	lines := []string{`// Send the requests this request depends on, storing their results in variables.`}
+	closing := []string{}
+	for depth, dep := range dependencies {
+		indent := strings.Repeat("    ", depth)
+		method := strings.ToUpper(defaultString(dep.Method, "GET"))
+		url, relative := p.dependencyURL(dep.Path, baseVariable)
+		requestHeaders := []string{}
+		if relative {
+			requestHeaders = headers
+		}
+
+		request := []string{
+			fmt.Sprintf(`url: pm.variables.replaceIn(%s)`, jsString(url)),
+			fmt.Sprintf(`method: %s`, jsString(method)),
+		}
+		if dep.Body != nil {
+			body, err := json.Marshal(dep.Body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid dependency %s %s: %w", method, dep.Path, err)
+			}
+			requestHeaders = append([]string{`"Content-Type": "application/json"`}, requestHeaders...)
+			request = append(request, fmt.Sprintf(`body: { mode: "raw", raw: pm.variables.replaceIn(%s) }`, jsString(string(body))))
+		}
+		if len(requestHeaders) > 0 {
+			request = append(request, "header: { "+strings.Join(requestHeaders, ", ")+" }")
+		}
+
+		lines = append(lines, indent+"pm.sendRequest({ "+strings.Join(request, ", ")+" }, function (err, res) {")
+		lines = append(lines,
+			indent+`    if (err || res.code >= 400) {`,
+			indent+fmt.Sprintf(`        console.error(%s, err || res.status);`, jsString(method+" "+dep.Path+" failed")),
+			indent+`        return;`,
+			indent+`    }`,
+		)
+		if len(dep.Extract) > 0 {
+			lines = append(lines, indent+`    const body = res.json();`)
+		}
+		for _, variable := range slices.Sorted(maps.Keys(dep.Extract)) {
+			keys, _ := json.Marshal(strings.Split(dep.Extract[variable], "."))
+			lines = append(lines, indent+fmt.Sprintf(`    pm.collectionVariables.set(%s, %s.reduce((obj, key) => obj == null ? undefined : obj[key], body));`, jsString(variable), keys))
+		}
+		closing = append(closing, indent+"});")
+	}
+	slices.Reverse(closing)
+	return append(lines, closing...), nil
+}
+
+// dependencyURL returns the URL of a dependency path for a route whose base
+// URL variable is baseVariable, and whether the path is relative to it.
+func (p *PostmanGen) dependencyURL(path string, baseVariable string) (string, bool) {
+	if strings.HasPrefix(path, "{{") || strings.Contains(path, "://") {
+		return path, false
+	}
+	base := p.baseURL.String()
+	if baseVariable != "" || p.baseURL == (BaseURL{}) {
+		base = "{{" + defaultString(baseVariable, p.baseURLVariable) + "}}"
+	}
+	return base + "/" + strings.TrimLeft(path, "/"), true
+}
+
+// bearerTokenVariable returns the variable the collection's bearer auth
+// sends, if it uses bearer auth.
+func (p *PostmanGen) bearerTokenVariable() (string, bool) {
+	auth := p.collection.Auth
+	if auth == nil || auth.Type != "bearer" {
+		return "", false
+	}
+	for _, param := range auth.Bearer {
+		if param.Key == "token" {
+			return strings.TrimSuffix(strings.TrimPrefix(fmt.Sprint(param.Value), "{{"), "}}"), true
+		}
+	}
+	return "token", true
+}
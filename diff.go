@@ -0,0 +1,461 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// DiffReport lists the endpoints that differ between a previously generated
+// collection and the current one. Endpoints are written as
+// "GET /users/:userId".
+type DiffReport struct {
+	// Added are the endpoints missing from the previous collection.
+	Added []string
+	// Removed are the endpoints missing from the current collection.
+	Removed []string
+	// Changed are the endpoints whose requests differ, including added or
+	// removed generated items such as negative tests.
+	Changed []string
+	// Changes classifies the differences, sorted by endpoint.
+	Changes []Change
+}
+
+// HasChanges reports whether any endpoint was added, removed or changed.
+func (r DiffReport) HasChanges() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// HasBreakingChanges reports whether any change can break existing clients,
+// e.g. to fail a CI job.
+func (r DiffReport) HasBreakingChanges() bool {
+	return slices.ContainsFunc(r.Changes, func(c Change) bool { return c.Breaking })
+}
+
+// ChangeKind is the kind of a Change.
+type ChangeKind string
+
+const (
+	RouteAdded   ChangeKind = "route added"
+	RouteRemoved ChangeKind = "route removed"
+	FieldAdded   ChangeKind = "field added"
+	FieldRemoved ChangeKind = "field removed"
+	TypeChanged  ChangeKind = "type changed"
+	// RequestChanged covers the differences of an endpoint that are none of
+	// the other kinds, such as changed examples or headers.
+	RequestChanged ChangeKind = "request changed"
+)
+
+// Change is a difference found by Diff.
+type Change struct {
+	Endpoint string
+	Kind     ChangeKind
+	// Field names the field of a field or type change, e.g. "body user.name",
+	// "query page", "path id" or "response email".
+	Field string
+	// Breaking is set for removed routes, removed required fields and type
+	// changes. Path variables and enabled query parameters count as required,
+	// disabled query parameters as optional. Body and response fields count
+	// as required unless the previous collection, written WithContentHashes
+	// or by Regenerate, lists them as optional: body fields with omitempty or
+	// a pointer type and no binding:"required" or validate:"required" tag,
+	// and response fields with omitempty or a pointer type.
+	Breaking bool
+}
+
+// String returns the change as "GET /users: field removed: body name
+// (breaking)".
+func (c Change) String() string {
+	s := c.Endpoint + ": " + string(c.Kind)
+	if c.Field != "" {
+		s += ": " + c.Field
+	}
+	if c.Breaking {
+		s += " (breaking)"
+	}
+	return s
+}
+
+// diffItem is a request of a collection document.
+type diffItem struct {
+	endpoint string
+	name     string
+	request  map[string]any
+	// fields maps the fields of the request and its first saved response to
+	// their JSON types.
+	fields map[string]diffField
+}
+
+type diffField struct {
+	typ      string
+	required bool
+}
+
+// Diff compares the collection with a previously generated one read from
+// previous, e.g. the committed file, endpoint by endpoint. Requests are
+// compared without volatile fields such as ids, along with the fields of the
+// first saved response; descriptions and scripts are ignored. The changes are
+// classified in DiffReport.Changes.
+func (p *PostmanGen) Diff(previous io.Reader) (DiffReport, error) {
+	var old map[string]any
+	if err := json.NewDecoder(previous).Decode(&old); err != nil {
+		return DiffReport{}, fmt.Errorf("failed to read previous collection: %w", err)
+	}
+	data, err := p.render()
+	if err != nil {
+		return DiffReport{}, err
+	}
+	var current map[string]any
+	if err := json.Unmarshal(data, &current); err != nil {
+		return DiffReport{}, err
+	}
+
+	oldItems := groupDiffItems(collectDiffItems(old["item"], nil))
+	newItems := groupDiffItems(collectDiffItems(current["item"], nil))
+
+	report := DiffReport{Added: []string{}, Removed: []string{}, Changed: []string{}, Changes: []Change{}}
+	for endpoint, items := range newItems {
+		previous, ok := oldItems[endpoint]
+		switch {
+		case !ok:
+			report.Added = append(report.Added, endpoint)
+			report.Changes = append(report.Changes, Change{Endpoint: endpoint, Kind: RouteAdded})
+		case !sameDiffItems(previous, items):
+			report.Changed = append(report.Changed, endpoint)
+			report.Changes = append(report.Changes, fieldChanges(endpoint, previous, items)...)
+		}
+	}
+	for endpoint := range oldItems {
+		if _, ok := newItems[endpoint]; !ok {
+			report.Removed = append(report.Removed, endpoint)
+			report.Changes = append(report.Changes, Change{Endpoint: endpoint, Kind: RouteRemoved, Breaking: true})
+		}
+	}
+	slices.Sort(report.Added)
+	slices.Sort(report.Removed)
+	slices.Sort(report.Changed)
+	slices.SortStableFunc(report.Changes, func(a, b Change) int {
+		return strings.Compare(a.Endpoint, b.Endpoint)
+	})
+	return report, nil
+}
+
+// fieldChanges classifies the differences of an endpoint's requests, matched
+// by item name.
+func fieldChanges(endpoint string, previous []diffItem, current []diffItem) []Change {
+	changes := []Change{}
+	add := func(c Change) {
+		if !slices.Contains(changes, c) {
+			changes = append(changes, c)
+		}
+	}
+
+	for _, item := range current {
+		i := slices.IndexFunc(previous, func(old diffItem) bool { return old.name == item.name })
+		if i < 0 {
+			continue
+		}
+		old := previous[i]
+
+		for _, name := range slices.Sorted(maps.Keys(old.fields)) {
+			was := old.fields[name]
+			now, ok := item.fields[name]
+			switch {
+			case !ok:
+				add(Change{Endpoint: endpoint, Kind: FieldRemoved, Field: name, Breaking: was.required})
+			case now.typ != was.typ:
+				add(Change{Endpoint: endpoint, Kind: TypeChanged, Field: name, Breaking: true})
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(item.fields)) {
+			if _, ok := old.fields[name]; !ok {
+				add(Change{Endpoint: endpoint, Kind: FieldAdded, Field: name})
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		add(Change{Endpoint: endpoint, Kind: RequestChanged})
+	}
+	return changes
+}
+
+// itemFields returns the fields of a collection item with their JSON types:
+// body fields, query parameters and path variables of the request, and the
+// body fields of its first saved response.
+func itemFields(item map[string]any, request map[string]any) map[string]diffField {
+	fields := map[string]diffField{}
+
+	if body, ok := request["body"].(map[string]any); ok {
+		switch body["mode"] {
+		case "raw":
+			if raw, ok := body["raw"].(string); ok {
+				var v any
+				if json.Unmarshal([]byte(raw), &v) == nil {
+					jsonFields("body", v, true, fields)
+				}
+			}
+		case "formdata", "urlencoded":
+			list, _ := body[body["mode"].(string)].([]any)
+			for _, entry := range list {
+				param, _ := entry.(map[string]any)
+				key, _ := param["key"].(string)
+				typ, _ := param["type"].(string)
+				fields["body "+key] = diffField{typ: defaultString(typ, "text"), required: true}
+			}
+		}
+	}
+
+	if u, ok := request["url"].(map[string]any); ok {
+		query, _ := u["query"].([]any)
+		for _, entry := range query {
+			param, _ := entry.(map[string]any)
+			key, _ := param["key"].(string)
+			disabled, _ := param["disabled"].(bool)
+			fields["query "+key] = diffField{typ: "string", required: !disabled}
+		}
+		variables, _ := u["variable"].([]any)
+		for _, entry := range variables {
+			variable, _ := entry.(map[string]any)
+			key, _ := variable["key"].(string)
+			fields["path "+key] = diffField{typ: "string", required: true}
+		}
+	}
+
+	if responses, ok := item["response"].([]any); ok && len(responses) > 0 {
+		response, _ := responses[0].(map[string]any)
+		if raw, ok := response["body"].(string); ok {
+			var v any
+			if json.Unmarshal([]byte(raw), &v) == nil {
+				jsonFields("response", v, true, fields)
+			}
+		}
+	}
+
+	// Collections written with content hashes list the optional fields.
+	marker, _ := item[regenerateKey].(map[string]any)
+	optional, _ := marker["optional"].([]any)
+	for _, name := range optional {
+		if field, ok := fields[fmt.Sprint(name)]; ok {
+			field.required = false
+			fields[fmt.Sprint(name)] = field
+		}
+	}
+	return fields
+}
+
+// jsonFields adds the fields of a JSON value to fields, named by their path
+// below prefix, e.g. "body user.name" or "body tags[]".
+func jsonFields(prefix string, v any, required bool, fields map[string]diffField) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			name := prefix + "." + key
+			if !strings.Contains(prefix, " ") {
+				name = prefix + " " + key
+			}
+			fields[name] = diffField{typ: jsonTypeName(value), required: required}
+			jsonFields(name, value, required, fields)
+		}
+	case []any:
+		for _, value := range v {
+			jsonFields(prefix+"[]", value, required, fields)
+		}
+	}
+}
+
+// jsonTypeName returns the JSON type of a decoded value.
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// collectDiffItems returns the requests of an item array, including those in
+// folders.
+func collectDiffItems(v any, items []diffItem) []diffItem {
+	list, _ := v.([]any)
+	for _, entry := range list {
+		item, _ := entry.(map[string]any)
+		if children, ok := item["item"]; ok {
+			items = collectDiffItems(children, items)
+			continue
+		}
+		request, ok := item["request"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		method, _ := request["method"].(string)
+		name, _ := item["name"].(string)
+		items = append(items, diffItem{
+			endpoint: strings.ToUpper(method) + " " + diffPath(request["url"]),
+			name:     name,
+			request:  comparableRequest(request),
+			fields:   itemFields(item, request),
+		})
+	}
+	return items
+}
+
+// diffPath returns the path of a request URL, without the base URL variable
+// and the query.
+func diffPath(v any) string {
+	switch u := v.(type) {
+	case string:
+		path, _, _ := strings.Cut(u, "?")
+		if strings.HasPrefix(path, "{{") {
+			if end := strings.Index(path, "}}"); end >= 0 {
+				path = path[end+2:]
+			}
+		}
+		return "/" + strings.TrimLeft(path, "/")
+	case map[string]any:
+		segments := []string{}
+		list, _ := u["path"].([]any)
+		for _, segment := range list {
+			if s, ok := segment.(string); ok {
+				segments = append(segments, s)
+			}
+		}
+		return "/" + strings.Join(segments, "/")
+	}
+	return "/"
+}
+
+// comparableRequest returns request without the fields Diff ignores.
+func comparableRequest(request map[string]any) map[string]any {
+	stripped, _ := stripVolatile(removeDescriptions(request)).(map[string]any)
+	return stripped
+}
+
+func removeDescriptions(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		copied := map[string]any{}
+		for key, value := range v {
+			if key != "description" {
+				copied[key] = removeDescriptions(value)
+			}
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, value := range v {
+			copied[i] = removeDescriptions(value)
+		}
+		return copied
+	}
+	return v
+}
+
+func groupDiffItems(items []diffItem) map[string][]diffItem {
+	groups := map[string][]diffItem{}
+	for _, item := range items {
+		groups[item.endpoint] = append(groups[item.endpoint], item)
+	}
+	return groups
+}
+
+// sameDiffItems reports whether two endpoints have the same requests and
+// response fields, matched by item name.
+func sameDiffItems(a []diffItem, b []diffItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, x := range a {
+		i := slices.IndexFunc(b, func(y diffItem) bool { return y.name == x.name })
+		if i < 0 || !reflect.DeepEqual(x.request, b[i].request) || !sameFieldTypes(x.fields, b[i].fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameFieldTypes reports whether a and b have the same fields with the same
+// types. Whether a field is required is only known from collections listing
+// their optional fields, so it is not compared.
+func sameFieldTypes(a map[string]diffField, b map[string]diffField) bool {
+	return maps.EqualFunc(a, b, func(x diffField, y diffField) bool { return x.typ == y.typ })
+}
+
+// optionalFields returns the JSON body and response fields of a route that
+// clients may omit, named as in Change.Field: body fields with omitempty or a
+// pointer type and no binding:"required" or validate:"required" tag, and
+// fields of the first typed response with omitempty or a pointer type.
+func (p *PostmanGen) optionalFields(rt *route) []string {
+	optional := []string{}
+	if rt.inputType != nil && !isProtoMessage(rt.inputType) {
+		optional = p.appendOptionalFields(optional, "body", derefType(rt.inputType), true, map[reflect.Type]bool{})
+	}
+	for _, r := range rt.responses {
+		if r.typ != nil {
+			optional = p.appendOptionalFields(optional, "response", derefType(r.typ), false, map[reflect.Type]bool{})
+			break
+		}
+	}
+	slices.Sort(optional)
+	return slices.Compact(optional)
+}
+
+// appendOptionalFields appends the optional fields of struct type t, named
+// below prefix as jsonFields names them, to optional. request selects the
+// rules of request bodies, where binding and validate tags make a field
+// required.
+func (p *PostmanGen) appendOptionalFields(optional []string, prefix string, t reflect.Type, request bool, visiting map[reflect.Type]bool) []string {
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return optional
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && derefType(f.Type).Kind() == reflect.Struct {
+			optional = p.appendOptionalFields(optional, prefix, derefType(f.Type), request, visiting)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = p.untaggedName(f.Name)
+		}
+
+		field := prefix + "." + name
+		if !strings.Contains(prefix, " ") {
+			field = prefix + " " + name
+		}
+		omittable := slices.Contains(strings.Split(options, ","), "omitempty") || f.Type.Kind() == reflect.Ptr
+		required := request && (fieldValidateRules(f).Required || parseValidateTag(f.Tag.Get("binding")).Required)
+		if omittable && !required {
+			optional = append(optional, field)
+		}
+
+		switch ft := derefType(f.Type); ft.Kind() {
+		case reflect.Struct:
+			optional = p.appendOptionalFields(optional, field, ft, request, visiting)
+		case reflect.Slice, reflect.Array:
+			optional = p.appendOptionalFields(optional, field+"[]", derefType(ft.Elem()), request, visiting)
+		}
+	}
+	return optional
+}
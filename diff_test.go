@@ -0,0 +1,135 @@
+package postmangen
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type diffUser struct {
+	Name     string `json:"name"`
+	Nickname string `json:"nickname,omitempty"`
+	Age      int    `json:"age"`
+}
+
+type diffUserWithEmail struct {
+	Name     string `json:"name"`
+	Nickname string `json:"nickname,omitempty"`
+	Age      int    `json:"age"`
+	Email    string `json:"email"`
+}
+
+type diffUserWithoutName struct {
+	Nickname string `json:"nickname,omitempty"`
+	Age      int    `json:"age"`
+}
+
+type diffUserWithoutNickname struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+type diffUserWithTextAge struct {
+	Name     string `json:"name"`
+	Nickname string `json:"nickname,omitempty"`
+	Age      string `json:"age"`
+}
+
+// generatorWithRoutes returns a generator with a POST route registered for
+// each of paths, taking input.
+func generatorWithRoutes(t *testing.T, input any, paths ...string) *PostmanGen {
+	t.Helper()
+	p := NewPostmanGen("API", "")
+	for _, path := range paths {
+		if err := p.Register(RouteSpec{"method": "POST", "path": path, "inputType": reflect.TypeOf(input)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		previous      *PostmanGen
+		contentHashes bool
+		current       *PostmanGen
+		want          []Change
+	}{
+		{
+			name:     "unchanged",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUser{}, "/users"),
+			want:     []Change{},
+		},
+		{
+			name:     "route added",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUser{}, "/users", "/admins"),
+			want:     []Change{{Endpoint: "POST /admins", Kind: RouteAdded}},
+		},
+		{
+			name:     "route removed",
+			previous: generatorWithRoutes(t, diffUser{}, "/users", "/admins"),
+			current:  generatorWithRoutes(t, diffUser{}, "/users"),
+			want:     []Change{{Endpoint: "POST /admins", Kind: RouteRemoved, Breaking: true}},
+		},
+		{
+			name:     "field added",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUserWithEmail{}, "/users"),
+			want:     []Change{{Endpoint: "POST /users", Kind: FieldAdded, Field: "body email"}},
+		},
+		{
+			name:          "required field removed",
+			previous:      generatorWithRoutes(t, diffUser{}, "/users"),
+			contentHashes: true,
+			current:       generatorWithRoutes(t, diffUserWithoutName{}, "/users"),
+			want:          []Change{{Endpoint: "POST /users", Kind: FieldRemoved, Field: "body name", Breaking: true}},
+		},
+		{
+			name:          "optional field removed",
+			previous:      generatorWithRoutes(t, diffUser{}, "/users"),
+			contentHashes: true,
+			current:       generatorWithRoutes(t, diffUserWithoutNickname{}, "/users"),
+			want:          []Change{{Endpoint: "POST /users", Kind: FieldRemoved, Field: "body nickname"}},
+		},
+		{
+			name:     "optional field removed without content hashes",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUserWithoutNickname{}, "/users"),
+			want:     []Change{{Endpoint: "POST /users", Kind: FieldRemoved, Field: "body nickname", Breaking: true}},
+		},
+		{
+			name:     "type changed",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUserWithTextAge{}, "/users"),
+			want:     []Change{{Endpoint: "POST /users", Kind: TypeChanged, Field: "body age", Breaking: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []WriteOption{}
+			if tt.contentHashes {
+				opts = append(opts, WithContentHashes())
+			}
+			previous, err := tt.previous.Bytes(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := tt.current.Diff(bytes.NewReader(previous))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(report.Changes, tt.want) {
+				t.Errorf("Diff().Changes = %v, want %v", report.Changes, tt.want)
+			}
+			wantBreaking := slices.ContainsFunc(tt.want, func(c Change) bool { return c.Breaking })
+			if report.HasBreakingChanges() != wantBreaking {
+				t.Errorf("HasBreakingChanges() = %v, want %v", report.HasBreakingChanges(), wantBreaking)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package postmangen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DiscoveredRoute is a handler registration found by DiscoverRoutes.
+type DiscoveredRoute struct {
+	// Method is empty for registrations matching every method, such as
+	// mux.HandleFunc("/x", h).
+	Method string
+	Path   string
+	// Handler is the handler expression, e.g. "h.CreateUser", and Position
+	// the place of the registration, e.g. "example.com/api/routes.go:42".
+	Handler  string
+	Position string
+	// Registered reports whether the collection has a route with the method
+	// and path.
+	Registered bool
+}
+
+// routerMethods maps the router methods registering a handler for one HTTP
+// method, as in chi, echo and gin, to that method.
+var routerMethods = map[string]string{}
+
+func init() {
+	for _, method := range standardMethods {
+		routerMethods[method] = method
+		routerMethods[strings.ToUpper(method[:1])+strings.ToLower(method[1:])] = method
+	}
+}
+
+// DiscoverRoutes scans the packages with the given import paths for handler
+// registrations and reports whether the collection has a route for each, so
+// handlers missing from the collection can be flagged or registered with
+// RegisterDiscovered. It recognizes calls with a literal path or pattern of
+//
+//   - Handle and HandleFunc, with Go 1.22 "GET /users/{id}" patterns, chained
+//     gorilla/mux Methods("GET") calls and httprouter's Handle("GET", path, h);
+//   - Get, Post, GET, POST and the other methods of chi, echo and gin routers;
+//   - chi's Method("GET", path, h).
+//
+// Paths are taken as written: the prefixes of route groups and mounted
+// routers are not resolved. Packages are parsed rather than compiled.
+func (p *PostmanGen) DiscoverRoutes(importPaths ...string) ([]DiscoveredRoute, error) {
+	discovered := []DiscoveredRoute{}
+	for _, importPath := range importPaths {
+		pkg, fset, files, err := parsePackage(importPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			for _, r := range discoverFileRoutes(file) {
+				pos := fset.Position(r.call.Pos())
+				route := DiscoveredRoute{
+					Method:   r.method,
+					Path:     r.path,
+					Handler:  r.handler,
+					Position: fmt.Sprintf("%s:%d", path.Join(pkg.ImportPath, path.Base(pos.Filename)), pos.Line),
+				}
+				route.Registered = p.hasRoute(route.Method, route.Path)
+				discovered = append(discovered, route)
+			}
+		}
+	}
+	return discovered, nil
+}
+
+// RegisterDiscovered registers the routes that are not registered yet, with
+// an empty input and a description naming their handler. Routes matching
+// every method are registered as GET. Failures are handled like in
+// RegisterAll.
+func (p *PostmanGen) RegisterDiscovered(routes ...DiscoveredRoute) error {
+	errs := []error{}
+	for _, r := range routes {
+		if r.Registered || p.hasRoute(r.Method, r.Path) {
+			continue
+		}
+		method := r.Method
+		if method == "" {
+			method = "GET"
+		}
+		err := p.Register(RouteSpec{
+			"method":      method,
+			"path":        r.Path,
+			"inputType":   reflect.TypeOf(struct{}{}),
+			"description": fmt.Sprintf("Discovered from %s at %s.", r.Handler, r.Position),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", method, r.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// hasRoute reports whether a route with method, or any method if empty, and
+// a path of the same shape is registered. Parameter names and styles are
+// ignored, so /users/{id} matches /users/:userID.
+func (p *PostmanGen) hasRoute(method string, routePath string) bool {
+	shape := pathShape(routePath)
+	for _, rt := range p.routes {
+		if (method == "" || strings.EqualFold(rt.method, method)) && pathShape(rt.path) == shape {
+			return true
+		}
+	}
+	return false
+}
+
+// pathShape returns routePath with its parameter segments replaced by ":" and
+// catch-all segments by "*".
+func pathShape(routePath string) string {
+	segments := strings.Split(strings.Trim(routePath, "/"), "/")
+	for i, segment := range segments {
+		if _, catchAll, ok := pathParamKey(segment); ok {
+			segments[i] = ":"
+			if catchAll {
+				segments[i] = "*"
+			}
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// discoveredCall is a handler registration found in a file.
+type discoveredCall struct {
+	call    *ast.CallExpr
+	method  string
+	path    string
+	handler string
+}
+
+// discoverFileRoutes returns the handler registrations of file in source
+// order.
+func discoverFileRoutes(file *ast.File) []discoveredCall {
+	calls := []discoveredCall{}
+	// chained maps Handle calls followed by gorilla/mux Methods calls to the
+	// methods, which are visited first.
+	chained := map[*ast.CallExpr][]string{}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		name := sel.Sel.Name
+		args := stringArgs(call.Args)
+
+		switch {
+		case name == "Methods":
+			if inner, ok := sel.X.(*ast.CallExpr); ok && len(args) > 0 {
+				chained[inner] = args
+			}
+		case (name == "Handle" || name == "HandleFunc") && len(args) >= 2 && isHTTPMethod(args[0]):
+			calls = append(calls, discoveredCall{call, strings.ToUpper(args[0]), args[1], handlerName(call.Args)})
+		case name == "Handle" || name == "HandleFunc":
+			if len(args) == 0 {
+				return true
+			}
+			method, pattern := "", args[0]
+			if m, rest, ok := strings.Cut(pattern, " "); ok && isHTTPMethod(m) {
+				method, pattern = m, strings.TrimSpace(rest)
+			}
+			// Go 1.22 patterns may start with a host.
+			if i := strings.Index(pattern, "/"); i > 0 {
+				pattern = pattern[i:]
+			}
+			pattern = strings.TrimSuffix(pattern, "{$}")
+			if methods, ok := chained[call]; ok {
+				for _, m := range methods {
+					calls = append(calls, discoveredCall{call, strings.ToUpper(m), pattern, handlerName(call.Args)})
+				}
+				return true
+			}
+			calls = append(calls, discoveredCall{call, method, pattern, handlerName(call.Args)})
+		case name == "Method" && len(args) >= 2 && isHTTPMethod(args[0]):
+			calls = append(calls, discoveredCall{call, strings.ToUpper(args[0]), args[1], handlerName(call.Args)})
+		case routerMethods[name] != "" && len(args) >= 1 && len(call.Args) >= 2 && strings.HasPrefix(args[0], "/"):
+			calls = append(calls, discoveredCall{call, routerMethods[name], args[0], handlerName(call.Args)})
+		}
+		return true
+	})
+	return calls
+}
+
+// stringArgs returns the leading string literal arguments of a call.
+func stringArgs(args []ast.Expr) []string {
+	values := []string{}
+	for _, arg := range args {
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			break
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			break
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// isHTTPMethod reports whether s is an HTTP method name.
+func isHTTPMethod(s string) bool {
+	for _, method := range standardMethods {
+		if strings.EqualFold(s, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// handlerName returns the handler argument of a registration, its last
+// argument, as written.
+func handlerName(args []ast.Expr) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return exprString(args[len(args)-1])
+}
+
+// exprString renders identifiers, selectors and calls such as
+// http.HandlerFunc(h.List); other expressions are described by their kind.
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.CallExpr:
+		args := []string{}
+		for _, arg := range e.Args {
+			args = append(args, exprString(arg))
+		}
+		return exprString(e.Fun) + "(" + strings.Join(args, ", ") + ")"
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	case *ast.FuncLit:
+		return "func literal"
+	case *ast.BasicLit:
+		return e.Value
+	}
+	return fmt.Sprintf("%T", expr)
+}
@@ -0,0 +1,117 @@
+package postmangen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// LoadDocComments reads the Go doc comments of the struct types and fields
+// declared in the packages with the given import paths, resolved from the
+// working directory like the go command does, e.g.
+// LoadDocComments("example.com/api/handlers"). Fields without a description
+// tag are then described by their comment, and routes without a description
+// by the comment of their input type, keeping documentation next to the code.
+// Packages are parsed, not compiled, so only their source is needed.
+func (p *PostmanGen) LoadDocComments(importPaths ...string) error {
+	for _, importPath := range importPaths {
+		pkg, _, files, err := parsePackage(importPath)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			p.addDocComments(pkg.ImportPath, file)
+		}
+	}
+	return nil
+}
+
+// parsePackage finds the package with the import path from the working
+// directory and parses its Go files with their comments.
+func parsePackage(importPath string) (*build.Package, *token.FileSet, []*ast.File, error) {
+	pkg, err := build.Import(importPath, ".", 0)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to find package %s: %w", importPath, err)
+	}
+
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to parse package %s: %w", importPath, err)
+		}
+		files = append(files, file)
+	}
+	return pkg, fset, files, nil
+}
+
+// addDocComments records the doc comments of the struct types of file, keyed
+// by "<import path>.<type>" and "<import path>.<type>.<field>".
+func (p *PostmanGen) addDocComments(importPath string, file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			key := importPath + "." + typeSpec.Name.Name
+			typeDoc := typeSpec.Doc
+			if typeDoc == nil && len(gen.Specs) == 1 {
+				typeDoc = gen.Doc
+			}
+			if text := commentText(typeDoc); text != "" {
+				p.docComments[key] = text
+			}
+
+			for _, field := range structType.Fields.List {
+				text := commentText(field.Doc)
+				if text == "" {
+					text = commentText(field.Comment)
+				}
+				if text == "" {
+					continue
+				}
+				for _, name := range field.Names {
+					p.docComments[key+"."+name.Name] = text
+				}
+			}
+		}
+	}
+}
+
+// commentText returns the text of a comment group with its lines joined.
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// typeDoc returns the doc comment loaded for the named type t.
+func (p *PostmanGen) typeDoc(t reflect.Type) string {
+	t = derefType(t)
+	if t.Name() == "" {
+		return ""
+	}
+	return p.docComments[t.PkgPath()+"."+t.Name()]
+}
+
+// fieldDoc returns the doc comment loaded for field f of struct type owner.
+func (p *PostmanGen) fieldDoc(owner reflect.Type, f reflect.StructField) string {
+	if owner == nil || owner.Name() == "" {
+		return ""
+	}
+	return p.docComments[owner.PkgPath()+"."+owner.Name()+"."+f.Name]
+}
@@ -0,0 +1,68 @@
+package postmangen
+
+import (
+	"reflect"
+	"strings"
+)
+
+// dynamicVariables maps normalized field names to the Postman dynamic
+// variables SetDynamicVariables uses for them.
+var dynamicVariables = map[string]string{
+	"email":        "$randomEmail",
+	"emailaddress": "$randomEmail",
+	"uuid":         "$guid",
+	"guid":         "$guid",
+	"firstname":    "$randomFirstName",
+	"lastname":     "$randomLastName",
+	"fullname":     "$randomFullName",
+	"username":     "$randomUserName",
+	"password":     "$randomPassword",
+	"phone":        "$randomPhoneNumber",
+	"phonenumber":  "$randomPhoneNumber",
+	"city":         "$randomCity",
+	"country":      "$randomCountry",
+	"url":          "$randomUrl",
+	"website":      "$randomUrl",
+	"ip":           "$randomIP",
+	"ipaddress":    "$randomIP",
+}
+
+// SetDynamicVariables makes requests send Postman dynamic variables, such as
+// {{$guid}} or {{$randomEmail}}, for fields without an example or
+// placeholder whose names or types have one, so every run uses fresh data:
+// email and uuid fields, names, phone numbers, URLs, IP addresses, integer
+// timestamp fields ({{$timestamp}}) and time.Time fields
+// ({{$isoTimestamp}}). A dynamic:"$randomInt" tag selects the variable of a
+// field explicitly, whether this is enabled or not, and takes precedence over
+// its example. Saved responses keep the examples.
+func (p *PostmanGen) SetDynamicVariables(enabled bool) *PostmanGen {
+	p.dynamicVariables = enabled
+	return p
+}
+
+// dynamicVariable returns the {{$variable}} reference sent for field, whose
+// keys are names, or false if it has none. Fields with an example only use the
+// variable of their dynamic tag.
+func (p *PostmanGen) dynamicVariable(field reflect.StructField, hasExample bool, names ...string) (string, bool) {
+	if tag := field.Tag.Get("dynamic"); tag != "" && tag != "-" {
+		return "{{$" + strings.TrimPrefix(tag, "$") + "}}", true
+	}
+	if !p.dynamicVariables || hasExample || field.Tag.Get("dynamic") == "-" {
+		return "", false
+	}
+
+	t := derefType(field.Type)
+	if t == timeType {
+		return "{{$isoTimestamp}}", true
+	}
+	for _, name := range names {
+		name = normalizedFieldName(name)
+		if name == "timestamp" && isNumberKind(t.Kind()) {
+			return "{{$timestamp}}", true
+		}
+		if variable, ok := dynamicVariables[name]; ok && t.Kind() == reflect.String {
+			return "{{" + variable + "}}", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,84 @@
+package postmangen
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// SetResponseEnvelope renders every 2xx response type inside envelope, the
+// struct wrapping the payloads of the API, e.g.
+//
+//	type Envelope struct {
+//		Meta Meta `json:"meta"`
+//	}
+//	pg.SetResponseEnvelope(reflect.TypeOf(Envelope{}), "data")
+//
+// renders {"data": <response>, "meta": {...}}. The response replaces the
+// envelope's dataKey member if it declares one. Routes can opt out with the
+// "responseEnvelope" spec key set to false. A nil envelope disables it.
+func (p *PostmanGen) SetResponseEnvelope(envelope reflect.Type, dataKey string) *PostmanGen {
+	p.responseEnvelope = envelope
+	p.envelopeKey = dataKey
+	return p
+}
+
+// envelopes reports whether the status response of the route spec is wrapped
+// in the response envelope.
+func (p *PostmanGen) envelopes(spec map[string]any, status int) bool {
+	if p.responseEnvelope == nil || derefType(p.responseEnvelope).Kind() != reflect.Struct {
+		return false
+	}
+	if enabled, ok := spec["responseEnvelope"].(bool); ok && !enabled {
+		return false
+	}
+	return status >= 200 && status < 300 && status != http.StatusNoContent
+}
+
+// envelopedType returns a struct type with the members of the response
+// envelope and t under the data key.
+func (p *PostmanGen) envelopedType(t reflect.Type) reflect.Type {
+	fields := []reflect.StructField{}
+	names := map[string]bool{}
+	dataIndex := 0
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			// Embedded structs are flattened, as reflect.StructOf does not
+			// promote their methods.
+			if f.Anonymous && name == "" && derefType(f.Type).Kind() == reflect.Struct {
+				collect(derefType(f.Type))
+				continue
+			}
+			if f.PkgPath != "" {
+				continue
+			}
+			if name == "" {
+				name = p.untaggedName(f.Name)
+			}
+			if name == p.envelopeKey {
+				dataIndex = len(fields)
+				continue
+			}
+			if names[f.Name] {
+				continue
+			}
+			f.Index, f.Offset, f.Anonymous = nil, 0, false
+			fields = append(fields, f)
+			names[f.Name] = true
+		}
+	}
+	collect(derefType(p.responseEnvelope))
+
+	data := reflect.StructField{Name: "Data", Type: t, Tag: reflect.StructTag(`json:"` + p.envelopeKey + `"`)}
+	for names[data.Name] {
+		data.Name += "_"
+	}
+	return reflect.StructOf(append(fields[:dataIndex:dataIndex], append([]reflect.StructField{data}, fields[dataIndex:]...)...))
+}
@@ -0,0 +1,57 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// environment is a Postman environment file.
+type environment struct {
+	Name   string             `json:"name"`
+	Values []environmentValue `json:"values"`
+	Scope  string             `json:"_postman_variable_scope"`
+}
+
+type environmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"` // default | secret
+	Enabled bool   `json:"enabled"`
+}
+
+// WriteEnvironment writes a Postman environment named after the collection,
+// holding the collection variables such as the base URL and token. Sensitive
+// variables get the secret type, and the write options mask their values as
+// they do for Write.
+func (p *PostmanGen) WriteEnvironment(w io.Writer, opts ...WriteOption) error {
+	data, err := p.environmentBytes(opts...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (p *PostmanGen) environmentBytes(opts ...WriteOption) ([]byte, error) {
+	settings := writeSettings{}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	env := environment{
+		Name:   p.collection.Info.Name,
+		Values: []environmentValue{},
+		Scope:  "environment",
+	}
+	for _, v := range p.collection.Variables {
+		value := environmentValue{Key: v.Key, Value: v.Value, Type: "default", Enabled: !v.Disabled}
+		if p.isSensitive(v.Key) {
+			value.Type = "secret"
+			if settings.secretValue != nil {
+				value.Value = settings.secretValue(v.Key)
+			}
+		}
+		env.Values = append(env.Values, value)
+	}
+	return json.MarshalIndent(env, "", "    ")
+}
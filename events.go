@@ -0,0 +1,57 @@
+package postmangen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// AddPreRequestScript appends lines to the collection-level prerequest script,
+// which Postman runs before every request in the collection.
+func (p *PostmanGen) AddPreRequestScript(lines ...string) *PostmanGen {
+	p.collection.Events = appendScript(p.collection.Events, postman.PreRequest, lines...)
+	return p
+}
+
+// AddTestScript appends lines to the collection-level test script, which
+// Postman runs after every request in the collection.
+func (p *PostmanGen) AddTestScript(lines ...string) *PostmanGen {
+	p.collection.Events = appendScript(p.collection.Events, postman.Test, lines...)
+	return p
+}
+
+// appendScript adds lines to the event listening on listen, creating the event
+// if needed. Postman only honors one script per listen type, so repeated calls
+// extend the existing script instead of adding another event.
+func appendScript(events []*postman.Event, listen postman.ListenType, lines ...string) []*postman.Event {
+	exec := splitScriptLines(lines)
+	if len(exec) == 0 {
+		return events
+	}
+
+	for _, event := range events {
+		if event.Listen == listen && event.Script != nil {
+			event.Script.Exec = append(event.Script.Exec, exec...)
+			return events
+		}
+	}
+
+	return append(events, postman.CreateEvent(listen, exec))
+}
+
+func splitScriptLines(lines []string) []string {
+	exec := []string{}
+	for _, line := range lines {
+		exec = append(exec, strings.Split(line, "\n")...)
+	}
+	return exec
+}
+
+func responseTimeScript(ms int) []string {
+	return []string{
+		fmt.Sprintf(`pm.test("Response time is below %dms", function () {`, ms),
+		fmt.Sprintf(`    pm.expect(pm.response.responseTime).to.be.below(%d);`, ms),
+		`});`,
+	}
+}
@@ -0,0 +1,178 @@
+package postmangen
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// ExampleValue returns an example of how a value of type t is rendered by
+// encoding/json, for use in response bodies. Struct fields use their `example`
+// tag or a default placeholder for their name or type when present, and the
+// zero value of their type otherwise.
+func (p *PostmanGen) ExampleValue(t reflect.Type) any {
+	return p.exampleValue(t, map[reflect.Type]bool{})
+}
+
+func (p *PostmanGen) exampleValue(t reflect.Type, visiting map[reflect.Type]bool) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if defaultValue, ok := p.typePlaceholders[t]; ok {
+		return coerceExample(defaultValue, t)
+	}
+	if isProtoMessage(t) {
+		return p.protoExample(t)
+	}
+
+	// Types with their own encoding are rendered the way they marshal their
+	// zero value, e.g. time.Time becomes "0001-01-01T00:00:00Z".
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		b, err := json.Marshal(reflect.New(t).Interface())
+		if err != nil {
+			return nil
+		}
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil
+		}
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if visiting[t] {
+			return nil
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		obj := map[string]any{}
+		p.collectExampleFields(t, obj, visiting)
+		return obj
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return ""
+		}
+		return []any{p.exampleValue(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]any{"key": p.exampleValue(t.Elem(), visiting)}
+	case reflect.Interface:
+		return nil
+	case reflect.Int64, reflect.Uint64:
+		if p.int64AsString {
+			return "0"
+		}
+	}
+
+	return reflect.Zero(t).Interface()
+}
+
+// collectExampleFields adds the JSON members of struct type t to obj,
+// flattening embedded structs without a json name like encoding/json does.
+func (p *PostmanGen) collectExampleFields(t reflect.Type, obj map[string]any, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			p.collectExampleFields(ft, obj, visiting)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = p.untaggedName(f.Name)
+		}
+
+		if override, ok := p.routeExample(name); ok {
+			obj[name] = override
+			if example, ok := override.(string); ok {
+				obj[name] = coerceExample(example, f.Type)
+			}
+		} else if example := f.Tag.Get("example"); example != "" && example != "-" {
+			obj[name] = coerceExample(example, f.Type)
+		} else if defaultValue, _, ok := p.namedPlaceholder(f, name); ok {
+			obj[name] = coerceExample(defaultValue, f.Type)
+		} else if defaultValue, ok := p.typePlaceholder(f.Type); ok {
+			obj[name] = coerceExample(defaultValue, f.Type)
+		} else if generated, ok := patternExample(f.Type, fieldValidateRules(f).Pattern); ok {
+			obj[name] = generated
+		} else if generated, ok := p.localeExample(f.Type, name); ok {
+			obj[name] = generated
+		} else if p.pointerOmitted(f.Type) {
+			continue
+		} else if p.pointerNull(f.Type) {
+			obj[name] = nil
+		} else {
+			obj[name] = p.exampleValue(f.Type, visiting)
+		}
+		if obj[name] != nil && p.encodedAsString(f) {
+			obj[name] = stringEncoded(obj[name], f.Type)
+		}
+	}
+}
+
+// coerceExample converts an example written in a struct tag to the JSON type
+// of t, so that `example:"42"` on an int is rendered as a number. Examples that
+// do not parse are kept as strings.
+func coerceExample(example string, t reflect.Type) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Bool:
+		if b, err := strconv.ParseBool(example); err == nil {
+			return b
+		}
+	case isFloatKind(t.Kind()):
+		if f, err := strconv.ParseFloat(example, 64); err == nil {
+			return f
+		}
+	case isNumberKind(t.Kind()):
+		if i, err := strconv.ParseInt(example, 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(example, 10, 64); err == nil {
+			return u
+		}
+	case t.Kind() == reflect.Struct, t.Kind() == reflect.Map, t.Kind() == reflect.Slice, t.Kind() == reflect.Array:
+		var v any
+		if err := json.Unmarshal([]byte(example), &v); err == nil {
+			return v
+		}
+	}
+
+	return example
+}
+
+// exampleJSON renders the example of t as a JSON document, formatted as
+// configured by SetBodyIndent and SetPreserveFieldOrder.
+func (p *PostmanGen) exampleJSON(t reflect.Type) (string, error) {
+	b, err := p.encodeJSONBody(p.ExampleValue(t), t)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
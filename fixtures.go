@@ -0,0 +1,87 @@
+package postmangen
+
+import (
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// Names of the folders of RegisterSetup and RegisterTeardown.
+const (
+	setupFolder    = "Setup"
+	teardownFolder = "Teardown"
+)
+
+// RegisterSetup registers a route like Register into a "Setup" folder placed
+// before the other requests, e.g. to create the fixtures they use. With the
+// "extract" spec key, members of its response are stored in collection
+// variables that later requests reference, so the collection runs top to
+// bottom in the collection runner:
+//
+//	pg.RegisterSetup(postmangen.RouteSpec{
+//		"method":    "POST",
+//		"path":      "/users",
+//		"inputType": reflect.TypeOf(CreateUserRequest{}),
+//		"extract":   map[string]string{"user_id": "id"},
+//	})
+//
+// Setup routes run in the order they are registered.
+func (p *PostmanGen) RegisterSetup(spec RouteSpec) error {
+	return p.registerFixture(spec, setupFolder)
+}
+
+// RegisterTeardown registers a route like Register into a "Teardown" folder
+// placed after the other requests, e.g. to delete the fixtures created by
+// setup routes, referenced through their extracted variables:
+//
+//	pg.RegisterTeardown(postmangen.RouteSpec{
+//		"method":    "DELETE",
+//		"path":      "/users/:id",
+//		"inputType": reflect.TypeOf(UserPath{}),
+//		"examples":  map[string]any{"id": "{{user_id}}"},
+//	})
+func (p *PostmanGen) RegisterTeardown(spec RouteSpec) error {
+	return p.registerFixture(spec, teardownFolder)
+}
+
+// registerFixture registers spec into the top-level folder, which is moved
+// first for setup routes and kept last for teardown routes.
+func (p *PostmanGen) registerFixture(spec RouteSpec, folder string) error {
+	if spec == nil {
+		return errors.New("invalid spec: must contain method, path, and inputType")
+	}
+	fixture := maps.Clone(spec)
+	fixture["folder"] = folder
+	if err := p.Register(fixture); err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(p.collection.Items, func(item *postman.Items) bool {
+		return item.Name == folder && item.IsGroup()
+	})
+	if i < 0 {
+		return nil
+	}
+	item := p.collection.Items[i]
+	switch folder {
+	case setupFolder:
+		p.collection.Items = slices.Insert(slices.Delete(p.collection.Items, i, i+1), 0, item)
+	case teardownFolder:
+		p.collection.Items = append(slices.Delete(p.collection.Items, i, i+1), item)
+		p.teardown = item
+	}
+	return nil
+}
+
+// appendItems appends items to list, before the folder of teardown routes
+// when list is the top level of the collection, so it stays last.
+func (p *PostmanGen) appendItems(list []*postman.Items, items ...*postman.Items) []*postman.Items {
+	if p.teardown != nil {
+		if i := slices.Index(list, p.teardown); i >= 0 {
+			return slices.Insert(list, i, items...)
+		}
+	}
+	return append(list, items...)
+}
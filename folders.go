@@ -0,0 +1,177 @@
+package postmangen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// FolderStrategy selects the folders a route is placed in from its path.
+type FolderStrategy int
+
+const (
+	// FolderByPath places a route in a folder per segment of its path before
+	// the last: GET /users/:userId/orders is the request "orders" in the
+	// folder users/:userId.
+	FolderByPath FolderStrategy = iota
+	// FolderByResource places a route in a folder per segment of its path
+	// that is not a parameter, so every route of a resource shares a folder:
+	// GET /users and GET /users/:userId are in the folder users, and GET
+	// /users/:userId/orders in the folder users/orders.
+	FolderByResource
+)
+
+// SetFolderStrategy selects how routes are grouped in folders by their path.
+// The "folder" spec key overrides it. The default is FolderByPath.
+func (p *PostmanGen) SetFolderStrategy(strategy FolderStrategy) *PostmanGen {
+	p.folderStrategy = strategy
+	return p
+}
+
+// resourceFolder returns the folders of a route with the FolderByResource
+// strategy, the segments of its path that are not parameters.
+func resourceFolder(pathSegments []string) []string {
+	folder := []string{}
+	for _, segment := range pathSegments {
+		_, _, param := pathParamKey(segment)
+		variable := strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}")
+		if segment != "" && !param && !variable {
+			folder = append(folder, segment)
+		}
+	}
+	return folder
+}
+
+// FolderMatching selects when a path segment names an existing folder or
+// request.
+type FolderMatching int
+
+const (
+	// FolderMatchExact matches names that are equal.
+	FolderMatchExact FolderMatching = iota
+	// FolderMatchCaseInsensitive matches names that are equal ignoring case,
+	// so /Users and /users share a folder.
+	FolderMatchCaseInsensitive
+	// FolderMatchNormalized matches names that are equal ignoring case and
+	// separators, so user-profiles, user_profiles and UserProfiles share a
+	// folder.
+	FolderMatchNormalized
+)
+
+// SetFolderMatching selects when routes are added to an existing folder, and
+// when a folder and a request collide. A merged folder keeps the name of the
+// first route creating it. The default is FolderMatchExact.
+func (p *PostmanGen) SetFolderMatching(matching FolderMatching) *PostmanGen {
+	p.folderMatching = matching
+	return p
+}
+
+// FolderCollision selects what happens when a folder and a request in the
+// same folder have the same name, such as the folder "users" of GET
+// /users/:id and the request "users" of GET /users.
+type FolderCollision int
+
+const (
+	// FolderCollisionAllow keeps the folder and the request side by side.
+	FolderCollisionAllow FolderCollision = iota
+	// FolderCollisionNest moves the request into the folder, so the folder
+	// holds the collection's requests and its members' requests.
+	FolderCollisionNest
+	// FolderCollisionError makes registering the colliding route fail.
+	FolderCollisionError
+)
+
+// SetFolderCollision selects what happens when a folder and a request have
+// the same name, as decided by SetFolderMatching. The default is
+// FolderCollisionAllow.
+func (p *PostmanGen) SetFolderCollision(collision FolderCollision) *PostmanGen {
+	p.folderCollision = collision
+	return p
+}
+
+// sameFolderName reports whether a and b name the same folder.
+func (p *PostmanGen) sameFolderName(a string, b string) bool {
+	switch p.folderMatching {
+	case FolderMatchCaseInsensitive:
+		return strings.EqualFold(a, b)
+	case FolderMatchNormalized:
+		return normalizeFolderName(a) == normalizeFolderName(b)
+	}
+	return a == b
+}
+
+// normalizeFolderName returns name in lower case without separators.
+func normalizeFolderName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// findFolder returns the folder of items named name.
+func (p *PostmanGen) findFolder(items []*postman.Items, name string) *postman.Items {
+	for _, item := range items {
+		if item.Request == nil && p.sameFolderName(item.Name, name) {
+			return item
+		}
+	}
+	return nil
+}
+
+// findRequest returns the index of the request of items named name, or -1.
+func (p *PostmanGen) findRequest(items []*postman.Items, name string) int {
+	return slices.IndexFunc(items, func(item *postman.Items) bool {
+		return item.Request != nil && p.sameFolderName(item.Name, name)
+	})
+}
+
+// checkFolderCollision returns an error if adding the request named
+// requestName to the folder at folderSegments makes a folder and a request
+// collide. An empty requestName only checks the folders.
+func (p *PostmanGen) checkFolderCollision(folderSegments []string, requestName string) error {
+	current := p.collection.Items
+	path := ""
+	for _, segment := range folderSegments {
+		if folder := p.findFolder(current, segment); folder != nil {
+			current, path = folder.Items, path+"/"+folder.Name
+			continue
+		}
+		if i := p.findRequest(current, segment); i >= 0 {
+			return fmt.Errorf("folder %s/%s collides with request %q", path, segment, current[i].Name)
+		}
+		current, path = nil, path+"/"+segment
+	}
+	if requestName != "" {
+		if folder := p.findFolder(current, requestName); folder != nil {
+			return fmt.Errorf("request %q collides with folder %s/%s", requestName, path, folder.Name)
+		}
+	}
+	return nil
+}
+
+// requestName returns the name of the first of items if it is a request, or
+// an empty string.
+func requestName(items []*postman.Items) string {
+	if len(items) > 0 && items[0].Request != nil {
+		return items[0].Name
+	}
+	return ""
+}
+
+// routeItemsIn returns the items of list registered with item by the same
+// route, such as its HEAD and OPTIONS siblings, starting with item.
+func (p *PostmanGen) routeItemsIn(list []*postman.Items, item *postman.Items) []*postman.Items {
+	for _, rt := range p.routes {
+		if slices.Contains(rt.items, item) {
+			return slices.DeleteFunc(slices.Clone(rt.items), func(routeItem *postman.Items) bool {
+				return !slices.Contains(list, routeItem)
+			})
+		}
+	}
+	return []*postman.Items{item}
+}
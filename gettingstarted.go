@@ -0,0 +1,116 @@
+package postmangen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// gettingStartedName is the name of the folder added by SetGettingStarted.
+const gettingStartedName = "Start Here"
+
+// SetGettingStarted prepends a "Start Here" folder without requests to the
+// collection, whose description explains the setup: the variables to fill
+// in, how requests are authenticated and how to import the environment
+// written by WriteEnvironment. It is generated when the collection is
+// written, from the variables and auth configured by then.
+func (p *PostmanGen) SetGettingStarted(enabled bool) *PostmanGen {
+	p.gettingStarted = enabled
+	return p
+}
+
+// withGettingStarted returns items with the folder added by SetGettingStarted
+// first, if enabled.
+func (p *PostmanGen) withGettingStarted(items []*postman.Items) []*postman.Items {
+	if !p.gettingStarted {
+		return items
+	}
+	folder := &postman.Items{
+		Name:        p.translate(gettingStartedName),
+		Description: p.gettingStartedDescription(),
+		Items:       []*postman.Items{},
+	}
+	return append([]*postman.Items{folder}, items...)
+}
+
+// gettingStartedDescription returns the description of the folder added by
+// SetGettingStarted.
+func (p *PostmanGen) gettingStartedDescription() string {
+	b := strings.Builder{}
+	b.WriteString("This folder explains how to set up the collection before sending requests.\n")
+
+	b.WriteString("\n## Variables\n\n")
+	baseVariables := p.undefinedBaseURLVariables()
+	if len(baseVariables) > 0 {
+		b.WriteString("Requests are sent to the base URL in these variables, which the collection does not define. Add them to your environment, e.g. with the value `https://api.example.com`:\n\n")
+		for _, name := range baseVariables {
+			b.WriteString("- `" + name + "`\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(p.collection.Variables) == 0 {
+		b.WriteString("The collection defines no variables.\n")
+	} else {
+		b.WriteString("Requests use these collection variables. Set them in the collection's Variables tab, or in the environment:\n\n")
+		for _, v := range p.collection.Variables {
+			b.WriteString("- `" + v.Key + "`")
+			switch {
+			case p.isSensitive(v.Key):
+				b.WriteString(": secret, fill in your own value")
+			case v.Value != "":
+				b.WriteString(": `" + v.Value + "` by default")
+			default:
+				b.WriteString(": no default, fill in a value")
+			}
+			if v.Disabled {
+				b.WriteString(" (disabled)")
+			}
+			if v.Description != "" {
+				b.WriteString(". " + strings.TrimSuffix(p.translate(v.Description), "."))
+			}
+			b.WriteString(".\n")
+		}
+	}
+
+	b.WriteString("\n## Authentication\n\n")
+	b.WriteString(p.authSetup() + "\n")
+
+	b.WriteString("\n## Environment\n\n")
+	b.WriteString("If an environment file was generated with the collection, import it with File > Import, select it in the environment selector and fill in the values of its secret variables. Environment values take precedence over the collection variables.\n")
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// undefinedBaseURLVariables returns the base URL variables of the routes
+// that are not collection variables.
+func (p *PostmanGen) undefinedBaseURLVariables() []string {
+	names := []string{}
+	for _, rt := range p.routes {
+		name := rt.baseVariable
+		if name == "" && p.baseURL == (BaseURL{}) {
+			name = p.baseURLVariable
+		}
+		if name != "" && p.variableIndex(name) < 0 && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// authSetup describes how requests are authenticated with the collection's
+// auth.
+func (p *PostmanGen) authSetup() string {
+	auth := p.collection.Auth
+	switch {
+	case p.sessionAuth != nil:
+		return fmt.Sprintf("Requests are authenticated by the `%s` session cookie. Send `%s %s` first: Postman's cookie jar stores the cookie and sends it with the other requests.",
+			p.sessionAuth.CookieName, p.sessionAuth.LoginMethod, p.sessionAuth.LoginPath)
+	case auth == nil || auth.Type == postman.NoAuth:
+		return "Requests are sent without authentication."
+	case auth.Type == postman.Bearer:
+		token, _ := p.bearerTokenVariable()
+		return fmt.Sprintf("Requests send the `%s` variable as a bearer token in the Authorization header. Set it to a valid access token.", token)
+	}
+	return fmt.Sprintf("Requests use the collection's %s auth. Fill in its credentials in the collection's Authorization tab.", auth.Type)
+}
@@ -2,4 +2,8 @@ module github.com/Lexographics/go-postmangen
 
 go 1.23.3
 
-require github.com/rbretecher/go-postman-collection v0.9.0
+require (
+	github.com/rbretecher/go-postman-collection v0.9.0
+	google.golang.org/protobuf v1.36.12
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,69 @@
+package postmangen
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+	"google.golang.org/protobuf/proto"
+)
+
+// RegisterGRPC adds an item for the gRPC method of service, e.g.
+// RegisterGRPC("users.v1.UserService", "GetUser", &userspb.GetUserRequest{}),
+// in a folder named after the service.
+//
+// The v2.1 collection format has no gRPC request type, so the item is the
+// HTTP/JSON form of the call used by Connect and gRPC transcoding proxies: a
+// POST to {{base_url}}/<service>/<method> with the protojson encoding of an
+// example requestMsg as body.
+func (p *PostmanGen) RegisterGRPC(service string, method string, requestMsg proto.Message) error {
+	if service == "" || method == "" || requestMsg == nil {
+		return errors.New("invalid gRPC method: must have service, method and request message")
+	}
+
+	inputType := reflect.TypeOf(requestMsg)
+	body, err := p.encodeJSONBody(p.protoExample(inputType), nil)
+	if err != nil {
+		return fmt.Errorf("failed to marshal gRPC message: %w", err)
+	}
+
+	pathSegments := []string{service, method}
+	baseVariable := p.routeBaseURLVariable(map[string]any{}, pathSegments)
+	request := &postman.Request{
+		URL:    p.requestURL(baseVariable, pathSegments, nil, nil),
+		Method: postman.Post,
+		Header: []*postman.Header{{Key: "Content-Type", Value: "application/json"}},
+		Body: &postman.Body{
+			Mode:    "raw",
+			Raw:     string(body),
+			Options: &postman.BodyOptions{Raw: postman.BodyOptionsRaw{Language: "json"}},
+		},
+	}
+
+	item := postman.CreateItem(postman.Item{
+		Name:        method,
+		Description: strings.TrimSpace(fmt.Sprintf("gRPC method %s/%s, called with its JSON encoding.\n\n%s", service, method, protoOneofDescription(inputType))),
+		Request:     request,
+	})
+	if behavior := p.protocolProfileBehaviorFor(map[string]any{}, false); behavior != nil {
+		item.ProtocolProfileBehavior = behavior
+	}
+
+	if err := p.addToFolder([]string{service}, item); err != nil {
+		return err
+	}
+
+	p.routes = append(p.routes, &route{
+		method:       "POST",
+		path:         "/" + service + "/" + method,
+		name:         method,
+		inputType:    inputType,
+		request:      request,
+		items:        []*postman.Items{item},
+		baseVariable: baseVariable,
+	})
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package postmangen
+
+import "net/http"
+
+// Handler returns an http.Handler serving the collection at /collection.json
+// and the environment written by WriteEnvironment at /environment.json, so a
+// running service can offer always up-to-date imports. Mount it under a
+// prefix with http.StripPrefix, and register all routes before serving. The
+// write options apply to both documents; use MaskSecrets when the endpoint is
+// reachable by others.
+func (p *PostmanGen) Handler(opts ...WriteOption) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /collection.json", func(w http.ResponseWriter, r *http.Request) {
+		serveJSON(w, func() ([]byte, error) { return p.render(opts...) })
+	})
+	mux.HandleFunc("GET /environment.json", func(w http.ResponseWriter, r *http.Request) {
+		serveJSON(w, func() ([]byte, error) { return p.environmentBytes(opts...) })
+	})
+	return mux
+}
+
+func serveJSON(w http.ResponseWriter, document func() ([]byte, error)) {
+	data, err := document()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
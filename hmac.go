@@ -0,0 +1,68 @@
+package postmangen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HMACSigning configures the pre-request script that signs requests with an
+// HMAC over their method, path and body. Empty fields use the defaults noted
+// on each field.
+type HMACSigning struct {
+	// SecretVariable holds the signing secret, "hmac_secret" by default.
+	SecretVariable string
+	// Algorithm is one of md5, sha1, sha256, sha384 and sha512, sha256 by
+	// default.
+	Algorithm string
+	// Header receives the signature, "X-Signature" by default.
+	Header string
+	// Prefix is written before the signature, e.g. "sha256=".
+	Prefix string
+	// Encoding of the signature, "hex" (default) or "base64".
+	Encoding string
+	// Separator is put between the method, path and body, which are
+	// concatenated directly by default.
+	Separator string
+}
+
+var hmacFunctions = map[string]string{
+	"md5":    "HmacMD5",
+	"sha1":   "HmacSHA1",
+	"sha256": "HmacSHA256",
+	"sha384": "HmacSHA384",
+	"sha512": "HmacSHA512",
+}
+
+// HMACSigningScript returns a pre-request script that computes an HMAC of the
+// request method, path with query and body using the secret variable, and sets
+// it as a header. Variables in the path and body are resolved first, so the
+// signature covers what is actually sent. The script is a block, so it can be
+// combined with other scripts. Add it to the collection with
+// AddPreRequestScript.
+func HMACSigningScript(cfg HMACSigning) (string, error) {
+	algorithm := strings.ToLower(defaultString(cfg.Algorithm, "sha256"))
+	function, ok := hmacFunctions[algorithm]
+	if !ok {
+		return "", fmt.Errorf("unsupported HMAC algorithm %q", cfg.Algorithm)
+	}
+
+	encoding := "Hex"
+	switch cfg.Encoding {
+	case "", "hex":
+	case "base64":
+		encoding = "Base64"
+	default:
+		return "", fmt.Errorf("unsupported signature encoding %q", cfg.Encoding)
+	}
+
+	lines := []string{
+		fmt.Sprintf(`// Sign the method, path and body with HMAC-%s.`, strings.ToUpper(algorithm)),
+		`{`,
+		`    const signedBody = pm.request.body ? pm.variables.replaceIn(pm.request.body.toString()) : "";`,
+		fmt.Sprintf(`    const signedMessage = [pm.request.method, pm.variables.replaceIn(pm.request.url.getPathWithQuery()), signedBody].join(%s);`, jsString(cfg.Separator)),
+		fmt.Sprintf(`    const signature = CryptoJS.%s(signedMessage, pm.variables.get(%s)).toString(CryptoJS.enc.%s);`, function, jsString(defaultString(cfg.SecretVariable, "hmac_secret")), encoding),
+		fmt.Sprintf(`    pm.request.headers.upsert({ key: %s, value: %s + signature });`, jsString(defaultString(cfg.Header, "X-Signature")), jsString(cfg.Prefix)),
+		`}`,
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,46 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SetInt64AsString renders int64 and uint64 values as strings in example
+// bodies, for APIs that encode 64-bit IDs as strings so JavaScript clients do
+// not lose precision. Fields with the `json:",string"` option are rendered as
+// strings regardless.
+func (p *PostmanGen) SetInt64AsString(enabled bool) *PostmanGen {
+	p.int64AsString = enabled
+	return p
+}
+
+// encodedAsString reports whether the value of field is written as a JSON
+// string: it has the ",string" json option, which encoding/json applies to
+// strings, booleans and numbers, or it is a 64-bit integer and
+// SetInt64AsString is enabled.
+func (p *PostmanGen) encodedAsString(field reflect.StructField) bool {
+	t := derefType(field.Type)
+	_, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if slices.Contains(strings.Split(opts, ","), "string") &&
+		(t.Kind() == reflect.String || t.Kind() == reflect.Bool || isNumberKind(t.Kind())) {
+		return true
+	}
+	return p.int64AsString && (t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64)
+}
+
+// stringEncoded returns the example v of a value of type t as written by
+// encoding/json with the ",string" option: numbers and booleans become
+// strings, and strings are quoted once more.
+func stringEncoded(v any, t reflect.Type) any {
+	if derefType(t).Kind() == reflect.String {
+		b, _ := json.Marshal(fmt.Sprint(v))
+		return string(b)
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,139 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteK6Script writes a k6 load test script exercising every registered route
+// with its example payload. Routes are grouped by their first path segment,
+// and the base URL and bearer token are read from the BASE_URL and TOKEN
+// environment variables, defaulting to the configured base URL and the token
+// collection variable. Routes with their own base URL variable read it from
+// the environment variable of the same name in upper case.
+func (p *PostmanGen) WriteK6Script(w io.Writer) error {
+	buf := &bytes.Buffer{}
+
+	buf.WriteString("import http from 'k6/http';\n")
+	buf.WriteString("import { check, group } from 'k6';\n\n")
+	fmt.Fprintf(buf, "const BASE_URL = __ENV.BASE_URL || %s;\n", jsString(p.baseURLValue()))
+	seen := map[string]bool{}
+	for _, r := range p.routes {
+		if r.baseVariable != "" && !seen[r.baseVariable] {
+			seen[r.baseVariable] = true
+			name := k6EnvName(r.baseVariable)
+			if r.baseVariable == p.baseURLVariable || name == "BASE_URL" || name == "TOKEN" {
+				continue
+			}
+			fmt.Fprintf(buf, "const %s = __ENV.%s || %s;\n", name, name, jsString(p.variableValue(r.baseVariable)))
+		}
+	}
+	fmt.Fprintf(buf, "const TOKEN = __ENV.TOKEN || %s;\n\n", jsString(p.variableValue("token")))
+	buf.WriteString("export const options = {\n  vus: 1,\n  iterations: 1,\n};\n\n")
+	buf.WriteString("export default function () {\n")
+
+	groups := []string{}
+	routesByGroup := map[string][]*route{}
+	for _, r := range p.routes {
+		group := strings.Split(strings.Trim(r.path, "/"), "/")[0]
+		if group == "" {
+			group = "root"
+		}
+		if _, ok := routesByGroup[group]; !ok {
+			groups = append(groups, group)
+		}
+		routesByGroup[group] = append(routesByGroup[group], r)
+	}
+
+	for _, group := range groups {
+		fmt.Fprintf(buf, "  group(%s, function () {\n", jsString(group))
+		for _, r := range routesByGroup[group] {
+			p.writeK6Request(buf, r)
+		}
+		buf.WriteString("  });\n")
+	}
+
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (p *PostmanGen) writeK6Request(buf *bytes.Buffer, r *route) {
+	method := strings.ToUpper(r.method)
+
+	base := "BASE_URL"
+	if r.baseVariable != "" && r.baseVariable != p.baseURLVariable {
+		base = k6EnvName(r.baseVariable)
+	}
+	url := "`${" + base + "}" + strings.ReplaceAll(p.resolveVariables(examplePath(r.request.URL)), "`", "\\`")
+	if query := exampleQuery(p.enabledQuery(r.request.URL)); query != "" {
+		url += "?" + query
+	}
+	url += "`"
+
+	body := "null"
+	if text, ok := bodyText(r.request.Body); ok {
+		body = jsString(text)
+	}
+
+	headers := []string{}
+	if p.collection.Auth != nil && p.collection.Auth.Type == "bearer" {
+		headers = append(headers, "\"Authorization\": `Bearer ${TOKEN}`")
+	}
+	for _, h := range r.request.Header {
+		if h.Key == "Content-Type" && body == "null" {
+			continue
+		}
+		headers = append(headers, jsString(h.Key)+": "+jsString(h.Value))
+	}
+
+	check := "(r) => r.status < 400"
+	label := method + " " + r.path + " succeeds"
+	if len(r.responses) > 0 {
+		check = fmt.Sprintf("(r) => r.status === %d", r.responses[0].status)
+		label = fmt.Sprintf("%s %s returns %d", method, r.path, r.responses[0].status)
+	}
+
+	buf.WriteString("    {\n")
+	fmt.Fprintf(buf, "      const res = http.request(%s, %s, %s, {\n", jsString(method), url, body)
+	fmt.Fprintf(buf, "        headers: { %s },\n", strings.Join(headers, ", "))
+	fmt.Fprintf(buf, "        tags: { name: %s },\n", jsString(method+" "+r.path))
+	buf.WriteString("      });\n")
+	fmt.Fprintf(buf, "      check(res, { %s: %s });\n", jsString(label), check)
+	buf.WriteString("    }\n")
+}
+
+// k6EnvName converts a Postman variable name to an environment variable and
+// JavaScript constant name, e.g. users_service_url to USERS_SERVICE_URL.
+func k6EnvName(variable string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, variable)
+}
+
+// variableValue returns the value of the collection variable key, or an empty
+// string if it is not defined or disabled.
+func (p *PostmanGen) variableValue(key string) string {
+	for _, v := range p.collection.Variables {
+		if v.Key == key && !v.Disabled {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+// jsString quotes s as a JavaScript string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
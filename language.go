@@ -0,0 +1,72 @@
+package postmangen
+
+import "reflect"
+
+// SetLanguage selects the language of the descriptions written to the
+// collection. Field descriptions are taken from a `description.<language>`
+// tag, e.g. `description.de:"Benutzername"`, route descriptions from the
+// language's entry when the "description" spec key is a map[string]string,
+// and other descriptions are translated with the map added by
+// AddTranslations. Descriptions without a translation are used as written.
+//
+// It must be called before registering the routes it should apply to.
+func (p *PostmanGen) SetLanguage(language string) *PostmanGen {
+	p.language = language
+	return p
+}
+
+// AddTranslations adds translations of descriptions into language, keyed by
+// the description as written in the code, e.g. the `description` tags and the
+// collection description.
+func (p *PostmanGen) AddTranslations(language string, translations map[string]string) *PostmanGen {
+	if p.translations[language] == nil {
+		p.translations[language] = map[string]string{}
+	}
+	for text, translated := range translations {
+		p.translations[language][text] = translated
+	}
+	return p
+}
+
+// translate returns text in the selected language, or text itself if it has
+// no translation.
+func (p *PostmanGen) translate(text string) string {
+	if translated, ok := p.translations[p.language][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// fieldDescription returns the description of field f of struct type owner in
+// the selected language, or its doc comment if it has no description tag.
+func (p *PostmanGen) fieldDescription(owner reflect.Type, f reflect.StructField) string {
+	if p.language != "" {
+		if description, ok := f.Tag.Lookup("description." + p.language); ok {
+			return description
+		}
+	}
+	if description := f.Tag.Get("description"); description != "" {
+		return p.translate(description)
+	}
+	return p.translate(p.fieldDoc(owner, f))
+}
+
+// routeDescription returns the "description" spec key in the selected
+// language. A map[string]string holds a description per language, with the
+// empty key as the fallback. Routes without one are described by the doc
+// comment of their input type.
+func (p *PostmanGen) routeDescription(spec map[string]any) string {
+	switch description := spec["description"].(type) {
+	case string:
+		return p.translate(description)
+	case map[string]string:
+		if text, ok := description[p.language]; ok {
+			return text
+		}
+		return p.translate(description[""])
+	}
+	if inputType, ok := spec["inputType"].(reflect.Type); ok {
+		return p.translate(p.typeDoc(inputType))
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package postmangen
+
+import "fmt"
+
+// truncatedMarker replaces the parts of example bodies cut by
+// SetExampleLimits.
+const truncatedMarker = "[truncated]"
+
+// SetExampleLimits limits the JSON example bodies of requests and saved
+// responses, so deeply nested aggregates do not produce huge bodies. Objects
+// and arrays nested deeper than maxDepth levels, counting the body itself as
+// the first, are replaced with "[truncated]". Bodies larger than maxBytes are
+// cut to fewer levels until they fit, and then lose their last members, which
+// are summarized by a "[truncated]" member. Zero disables a limit, which is
+// the default for both.
+func (p *PostmanGen) SetExampleLimits(maxDepth int, maxBytes int) *PostmanGen {
+	p.maxExampleDepth = max(maxDepth, 0)
+	p.maxExampleBytes = max(maxBytes, 0)
+	return p
+}
+
+// limitExample formats node within the limits set by SetExampleLimits.
+func (p *PostmanGen) limitExample(node *orderedNode) ([]byte, error) {
+	depth := nodeDepth(node)
+	if p.maxExampleDepth > 0 {
+		depth = min(depth, p.maxExampleDepth)
+	}
+
+	for {
+		b, err := p.formatJSON(truncateDepth(node, depth))
+		if err != nil || p.maxExampleBytes == 0 || len(b) <= p.maxExampleBytes {
+			return b, err
+		}
+		if depth <= 1 {
+			break
+		}
+		depth--
+	}
+
+	// The top level alone is too large: drop members from the end.
+	root := truncateDepth(node, 1)
+	for keep := len(root.values) - 1; keep >= 0; keep-- {
+		cut := &orderedNode{kind: root.kind, values: root.values[:keep:keep]}
+		marker := &orderedNode{kind: 'v', scalar: truncatedMarker}
+		if root.kind == 'o' {
+			cut.keys = append(root.keys[:keep:keep], truncatedMarker)
+			marker.scalar = fmt.Sprintf("%d more members", len(root.values)-keep)
+		}
+		cut.values = append(cut.values, marker)
+
+		b, err := p.formatJSON(cut)
+		if err != nil || len(b) <= p.maxExampleBytes || keep == 0 {
+			return b, err
+		}
+	}
+	return p.formatJSON(root)
+}
+
+// nodeDepth returns the number of object and array levels of node.
+func nodeDepth(node *orderedNode) int {
+	if node.kind == 'v' {
+		return 0
+	}
+	depth := 0
+	for _, value := range node.values {
+		depth = max(depth, nodeDepth(value))
+	}
+	return depth + 1
+}
+
+// truncateDepth returns node with the non-empty objects and arrays below
+// depth levels replaced by the truncated marker.
+func truncateDepth(node *orderedNode, depth int) *orderedNode {
+	if node.kind == 'v' || len(node.values) == 0 {
+		return node
+	}
+	if depth <= 0 {
+		return &orderedNode{kind: 'v', scalar: truncatedMarker}
+	}
+	cut := &orderedNode{kind: node.kind, keys: node.keys, values: make([]*orderedNode, len(node.values))}
+	for i, value := range node.values {
+		cut.values[i] = truncateDepth(value, depth-1)
+	}
+	return cut
+}
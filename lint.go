@@ -0,0 +1,99 @@
+package postmangen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// Lint rules reported in LintIssue.Rule.
+const (
+	LintMissingDescription = "missing-description"
+	LintMissingExample     = "missing-example"
+	LintUnusedPlaceholder  = "unused-placeholder"
+	LintEmptyFolder        = "empty-folder"
+)
+
+// LintIssue is a documentation quality problem found by Lint.
+type LintIssue struct {
+	Rule string
+	// Target is the route ("POST /users"), placeholder key or folder path the
+	// issue is about.
+	Target  string
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Target, i.Message, i.Rule)
+}
+
+// Lint reports documentation gaps in the registered routes: routes without a
+// description, input fields that fall back to zero values because they have
+// no example or placeholder, placeholders no field used, and folders without
+// requests. An empty result means the collection passes.
+func (p *PostmanGen) Lint() []LintIssue {
+	issues := []LintIssue{}
+
+	for _, r := range p.routes {
+		target := strings.ToUpper(r.method) + " " + r.path
+		if r.description == "" {
+			issues = append(issues, LintIssue{
+				Rule:    LintMissingDescription,
+				Target:  target,
+				Message: "route has no description",
+			})
+		}
+		for _, field := range r.missingExamples {
+			issues = append(issues, LintIssue{
+				Rule:    LintMissingExample,
+				Target:  target,
+				Message: fmt.Sprintf("field %s has no example", field),
+			})
+		}
+	}
+
+	unused := []string{}
+	for key := range p.placeholderDefaults {
+		if !p.usedPlaceholders[key] {
+			unused = append(unused, key)
+		}
+	}
+	for key := range p.placeholderFuncs {
+		if _, ok := p.placeholderDefaults[key]; !ok && !p.usedPlaceholders[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	for _, key := range unused {
+		issues = append(issues, LintIssue{
+			Rule:    LintUnusedPlaceholder,
+			Target:  key,
+			Message: "placeholder is not used by any field",
+		})
+	}
+
+	issues = append(issues, emptyFolders(p.collection.Items, "")...)
+
+	return issues
+}
+
+func emptyFolders(items []*postman.Items, parent string) []LintIssue {
+	issues := []LintIssue{}
+	for _, item := range items {
+		if !item.IsGroup() {
+			continue
+		}
+		path := parent + "/" + item.Name
+		if len(item.Items) == 0 {
+			issues = append(issues, LintIssue{
+				Rule:    LintEmptyFolder,
+				Target:  path,
+				Message: "folder has no requests",
+			})
+		}
+		issues = append(issues, emptyFolders(item.Items, path)...)
+	}
+	return issues
+}
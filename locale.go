@@ -0,0 +1,96 @@
+package postmangen
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// localeData holds the example values of a locale.
+type localeData struct {
+	firstName   string
+	lastName    string
+	street      string
+	city        string
+	postalCode  string
+	phone       string
+	country     string
+	countryCode string
+	emailDomain string
+}
+
+var locales = map[string]localeData{
+	"en-US": {"John", "Smith", "742 Evergreen Terrace", "Springfield", "62704", "+1 555-0100", "United States", "US", "example.com"},
+	"en-GB": {"Oliver", "Taylor", "221B Baker Street", "London", "NW1 6XE", "+44 20 7946 0958", "United Kingdom", "GB", "example.co.uk"},
+	"de-DE": {"Max", "Mustermann", "Musterstraße 12", "Berlin", "10115", "+49 30 12345678", "Deutschland", "DE", "example.de"},
+	"fr-FR": {"Jean", "Dupont", "12 rue de la Paix", "Paris", "75002", "+33 1 23 45 67 89", "France", "FR", "example.fr"},
+	"es-ES": {"Juan", "García", "Calle Mayor 5", "Madrid", "28013", "+34 912 345 678", "España", "ES", "example.es"},
+	"it-IT": {"Mario", "Rossi", "Via Roma 10", "Roma", "00184", "+39 06 1234 5678", "Italia", "IT", "example.it"},
+	"nl-NL": {"Jan", "Jansen", "Damstraat 1", "Amsterdam", "1012 JS", "+31 20 123 4567", "Nederland", "NL", "example.nl"},
+	"pl-PL": {"Jan", "Kowalski", "ul. Marszałkowska 1", "Warszawa", "00-624", "+48 22 123 45 67", "Polska", "PL", "example.pl"},
+}
+
+// SetLocale enables generated examples for string fields without an example
+// or placeholder whose names denote personal or address data, such as
+// first_name, full_name, email, phone, street, city, postal_code and country,
+// formatted for locale. Supported locales are en-US, en-GB, de-DE, fr-FR,
+// es-ES, it-IT, nl-NL and pl-PL; an empty locale disables the examples, which
+// is the default.
+func (p *PostmanGen) SetLocale(locale string) *PostmanGen {
+	p.locale = locale
+	return p
+}
+
+// localeExample returns the example of the locale for a string field named
+// by one of names, or false if there is none.
+func (p *PostmanGen) localeExample(t reflect.Type, names ...string) (string, bool) {
+	data, ok := locales[p.locale]
+	if !ok || derefType(t).Kind() != reflect.String {
+		return "", false
+	}
+
+	for _, name := range names {
+		switch normalizedFieldName(name) {
+		case "firstname", "givenname", "forename":
+			return data.firstName, true
+		case "lastname", "surname", "familyname":
+			return data.lastName, true
+		case "fullname", "displayname":
+			return data.firstName + " " + data.lastName, true
+		case "email", "emailaddress", "mail":
+			return strings.ToLower(asciiName(data.firstName)+"."+asciiName(data.lastName)) + "@" + data.emailDomain, true
+		case "phone", "phonenumber", "mobile", "telephone", "tel":
+			return data.phone, true
+		case "street", "streetaddress", "address", "addressline", "addressline1":
+			return data.street, true
+		case "city", "town", "locality":
+			return data.city, true
+		case "postalcode", "postcode", "zip", "zipcode":
+			return data.postalCode, true
+		case "country", "countryname":
+			return data.country, true
+		case "countrycode":
+			return data.countryCode, true
+		case "locale", "language":
+			return p.locale, true
+		}
+	}
+	return "", false
+}
+
+// normalizedFieldName returns name in lower case without separators, so
+// first_name, firstName and FirstName are equal.
+func normalizedFieldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// asciiName replaces the letters of name that are not valid in the local part
+// of an email address.
+func asciiName(name string) string {
+	return strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss", "é", "e", "è", "e", "í", "i", "á", "a", "ó", "o", "ñ", "n", "ł", "l").Replace(name)
+}
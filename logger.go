@@ -0,0 +1,51 @@
+package postmangen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Logger receives debug output. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// SetLogger makes Register report what it derives from each struct field:
+// the tags it uses and where the example value comes from. This helps
+// diagnose why a parameter is missing or has an unexpected value. A nil
+// logger disables the output.
+func (p *PostmanGen) SetLogger(logger Logger) *PostmanGen {
+	p.logger = logger
+	return p
+}
+
+func (p *PostmanGen) debugf(format string, v ...any) {
+	if p.logger != nil {
+		p.logger.Printf(format, v...)
+	}
+}
+
+// logField reports how Register used a struct field of a route's input.
+func (p *PostmanGen) logField(method string, path string, field reflect.StructField, example any, source string) {
+	if p.logger == nil {
+		return
+	}
+
+	uses := []string{}
+	for _, tag := range []string{"json", "form", "formFile", "query", "param"} {
+		if value := field.Tag.Get(tag); value != "" && value != "-" {
+			uses = append(uses, fmt.Sprintf("%s %q", tag, value))
+		}
+	}
+	if len(uses) == 0 {
+		p.debugf("%s %s: field %s has no json, form, formFile, query or param tag and is ignored", method, path, field.Name)
+		return
+	}
+
+	if source == "zero value" {
+		p.debugf("%s %s: field %s used as %s, no example tag or placeholder, using the zero value", method, path, field.Name, strings.Join(uses, ", "))
+		return
+	}
+	p.debugf("%s %s: field %s used as %s, example %q from %s", method, path, field.Name, strings.Join(uses, ", "), fmt.Sprint(example), source)
+}
@@ -0,0 +1,66 @@
+package postmangen
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// matrixParam is a matrix parameter appended to the last path segment, e.g.
+// ;color=red.
+type matrixParam struct {
+	key         string
+	value       string
+	description string
+}
+
+// withMatrixParams returns a copy of pathSegments with params appended to the
+// last segment.
+func withMatrixParams(pathSegments []string, params []matrixParam) []string {
+	if len(params) == 0 {
+		return pathSegments
+	}
+	segments := append([]string{}, pathSegments...)
+	for _, param := range params {
+		segments[len(segments)-1] += ";" + param.key + "=" + param.value
+	}
+	return segments
+}
+
+// escapeMatrixSegment percent-encodes a path segment with matrix parameters,
+// keeping the ; and = separators.
+func escapeMatrixSegment(segment string) string {
+	parts := strings.Split(segment, ";")
+	parts[0] = escapeURLPart(parts[0], url.PathEscape)
+	for i, part := range parts[1:] {
+		key, value, hasValue := strings.Cut(part, "=")
+		parts[i+1] = escapeURLPart(key, url.PathEscape)
+		if hasValue {
+			parts[i+1] += "=" + escapeURLPart(value, url.PathEscape)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// urlPartsDescription documents the matrix parameters and fragment of a
+// route's URL.
+func urlPartsDescription(params []matrixParam, fragment string) string {
+	lines := []string{}
+	if len(params) > 0 {
+		lines = append(lines, "**Matrix parameters** (on the last path segment):")
+		for _, param := range params {
+			line := fmt.Sprintf("- `%s`", param.key)
+			if param.description != "" {
+				line += ": " + param.description
+			}
+			lines = append(lines, line)
+		}
+	}
+	if fragment != "" {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("**URL fragment:** `#%s`", fragment))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,34 @@
+package postmangen
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// standardMethods are the HTTP methods Register accepts without AllowMethods.
+var standardMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+// AllowMethods lets Register accept extension methods besides the standard
+// ones, e.g. AllowMethods("PROPFIND", "PURGE").
+func (p *PostmanGen) AllowMethods(methods ...string) *PostmanGen {
+	for _, method := range methods {
+		p.allowedMethods = append(p.allowedMethods, strings.ToUpper(method))
+	}
+	return p
+}
+
+// validateMethod returns an error for methods that are neither standard nor
+// allowed with AllowMethods, so typos such as "PSOT" are not written as
+// broken requests. Methods are compared case-insensitively.
+func (p *PostmanGen) validateMethod(method string) error {
+	upper := strings.ToUpper(method)
+	if slices.Contains(standardMethods, upper) || slices.Contains(p.allowedMethods, upper) {
+		return nil
+	}
+	return fmt.Errorf("invalid method %q: must be a standard HTTP method or allowed with AllowMethods", method)
+}
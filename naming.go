@@ -0,0 +1,74 @@
+package postmangen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy selects the JSON names of struct fields without a json tag
+// name.
+type NamingStrategy int
+
+const (
+	// NamingAsIs uses the Go field name, like encoding/json: TenantID.
+	NamingAsIs NamingStrategy = iota
+	// NamingSnakeCase uses lower case words joined by underscores: tenant_id.
+	NamingSnakeCase
+	// NamingCamelCase uses words joined in lower camel case: tenantId.
+	NamingCamelCase
+)
+
+// SetNamingStrategy selects how fields without a json tag name, including
+// fields tagged only with options such as `json:",omitempty"`, are named in
+// example bodies and JSON Schemas, to match encoders configured with a naming
+// policy. The default is NamingAsIs.
+func (p *PostmanGen) SetNamingStrategy(strategy NamingStrategy) *PostmanGen {
+	p.namingStrategy = strategy
+	return p
+}
+
+// untaggedName returns the JSON name of the Go field name for fields without
+// a json tag name.
+func (p *PostmanGen) untaggedName(name string) string {
+	switch p.namingStrategy {
+	case NamingSnakeCase:
+		words := nameWords(name)
+		for i, word := range words {
+			words[i] = strings.ToLower(word)
+		}
+		return strings.Join(words, "_")
+	case NamingCamelCase:
+		words := nameWords(name)
+		for i, word := range words {
+			word = strings.ToLower(word)
+			if i > 0 {
+				word = strings.ToUpper(word[:1]) + word[1:]
+			}
+			words[i] = word
+		}
+		return strings.Join(words, "")
+	}
+	return name
+}
+
+// nameWords splits a Go identifier into words at underscores and case
+// changes, keeping acronyms together: HTTPServerID becomes HTTP, Server, ID.
+func nameWords(name string) []string {
+	words := []string{}
+	for _, part := range strings.Split(name, "_") {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, cur := runes[i-1], runes[i]
+			acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if (unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsUpper(cur) || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			words = append(words, string(runes[start:]))
+		}
+	}
+	return words
+}
@@ -0,0 +1,179 @@
+package postmangen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// SetNegativeTests enables negative test generation for every registered
+// route. For each JSON body field carrying a `validate` tag, sibling requests
+// are emitted with an invalid payload (field missing, below min, above max,
+// wrong type), each with a test expecting a 4xx status. Routes can override it
+// with the "negativeTests" spec key.
+func (p *PostmanGen) SetNegativeTests(enabled bool) *PostmanGen {
+	p.negativeTests = enabled
+	return p
+}
+
+// jsonBodyField is a field rendered into a route's JSON body.
+type jsonBodyField struct {
+	key   string
+	field reflect.StructField
+}
+
+// negativeCase is a JSON body with a single invalid field.
+type negativeCase struct {
+	name   string
+	key    string
+	value  any
+	remove bool
+}
+
+// negativeCases derives the invalid payload variations for fields from their
+// validate and pattern tags.
+func negativeCases(fields []jsonBodyField) []negativeCase {
+	cases := []negativeCase{}
+
+	for _, f := range fields {
+		rules := fieldValidateRules(f.field)
+		if f.field.Tag.Get("validate") == "" && rules.Pattern == "" {
+			continue
+		}
+
+		t := f.field.Type
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if rules.Required {
+			cases = append(cases, negativeCase{name: "missing " + f.key, key: f.key, remove: true})
+		}
+		if rules.Min != nil {
+			if value, ok := outOfRangeValue(t, *rules.Min, rules.MinExclusive, -1); ok {
+				cases = append(cases, negativeCase{name: f.key + " below min", key: f.key, value: value})
+			}
+		}
+		if rules.Max != nil {
+			if value, ok := outOfRangeValue(t, *rules.Max, rules.MaxExclusive, 1); ok {
+				cases = append(cases, negativeCase{name: f.key + " above max", key: f.key, value: value})
+			}
+		}
+		if rules.Email && t.Kind() == reflect.String {
+			cases = append(cases, negativeCase{name: f.key + " not an email", key: f.key, value: "not-an-email"})
+		}
+		if len(rules.OneOf) > 0 && t.Kind() == reflect.String {
+			cases = append(cases, negativeCase{name: f.key + " not one of allowed values", key: f.key, value: "not-" + strings.Join(rules.OneOf, "-")})
+		}
+		if value, ok := patternMismatch(rules.Pattern); ok && t.Kind() == reflect.String {
+			cases = append(cases, negativeCase{name: f.key + " not matching pattern", key: f.key, value: value})
+		}
+		cases = append(cases, negativeCase{name: f.key + " wrong type", key: f.key, value: wrongTypeValue(t)})
+	}
+
+	return cases
+}
+
+// outOfRangeValue returns a value of type t just past limit in direction dir.
+// Limits apply to the length of strings and slices and to the value of numbers;
+// an exclusive limit is itself out of range.
+func outOfRangeValue(t reflect.Type, limit float64, exclusive bool, dir int) (any, bool) {
+	if exclusive {
+		return valueOfSize(t, limit)
+	}
+	return valueOfSize(t, limit+float64(dir)*sizeStep(t))
+}
+
+// valueOfSize returns a string or slice of length n, or the number n, as a
+// value of type t.
+func valueOfSize(t reflect.Type, n float64) (any, bool) {
+	switch {
+	case t.Kind() == reflect.String:
+		if n < 0 {
+			return nil, false
+		}
+		return strings.Repeat("a", int(n)), true
+	case t.Kind() == reflect.Slice:
+		if n < 0 {
+			return nil, false
+		}
+		return make([]any, int(n)), true
+	case isFloatKind(t.Kind()):
+		return n, true
+	case isNumberKind(t.Kind()):
+		return int64(n), true
+	}
+	return nil, false
+}
+
+// sizeStep is the smallest meaningful change of a constrained value of type t.
+func sizeStep(t reflect.Type) float64 {
+	if isFloatKind(t.Kind()) {
+		return 0.1
+	}
+	return 1
+}
+
+func wrongTypeValue(t reflect.Type) any {
+	if t.Kind() == reflect.String {
+		return 12345
+	}
+	return "invalid"
+}
+
+func negativeTestScript() []string {
+	return []string{
+		`pm.test("Invalid input is rejected with a 4xx status", function () {`,
+		`    pm.expect(pm.response.code).to.be.within(400, 499);`,
+		`});`,
+	}
+}
+
+// negativeItems builds one request per negative case, copying request with
+// the invalid JSON body.
+func (p *PostmanGen) negativeItems(name string, request *postman.Request, params map[string]any, unquotedRefs []string, fields []jsonBodyField) ([]*postman.Items, error) {
+	items := []*postman.Items{}
+
+	for _, c := range negativeCases(fields) {
+		invalid := make(map[string]any, len(params))
+		for k, v := range params {
+			invalid[k] = v
+		}
+		if c.remove {
+			delete(invalid, c.key)
+		} else {
+			invalid[c.key] = c.value
+		}
+
+		raw, err := p.marshalJSONBody(invalid, unquotedRefs, fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal json body: %w", err)
+		}
+
+		items = append(items, postman.CreateItem(postman.Item{
+			Name:      fmt.Sprintf("%s [invalid: %s]", name, c.name),
+			Request:   copyRequestWithBody(request, raw),
+			Responses: []*postman.Response{},
+			Events:    appendScript(nil, postman.Test, negativeTestScript()...),
+		}))
+	}
+
+	return items, nil
+}
+
+// copyRequestWithBody returns a copy of request whose raw body is replaced.
+func copyRequestWithBody(request *postman.Request, raw string) *postman.Request {
+	copied := *request
+
+	url := *request.URL
+	copied.URL = &url
+	copied.Header = append([]*postman.Header{}, request.Header...)
+
+	body := *request.Body
+	body.Raw = raw
+	copied.Body = &body
+
+	return &copied
+}
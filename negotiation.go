@@ -0,0 +1,140 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// specProduces returns the content types of the "produces" spec key, the
+// alternatives a route returns depending on the Accept header.
+func specProduces(spec map[string]any) []string {
+	produces, _ := spec["produces"].([]string)
+	return produces
+}
+
+// negotiatedResponse builds the saved example of r, which has a type, in
+// contentType, answering a copy of request that asks for it with an Accept
+// header.
+func (p *PostmanGen) negotiatedResponse(request *postman.Request, r routeResponse, contentType string) (*postman.Response, error) {
+	response, err := p.savedResponse(acceptRequest(request, contentType), r)
+	if err != nil {
+		return nil, err
+	}
+	response.Name = fmt.Sprintf("%s (%s)", response.Name, contentType)
+
+	body, err := p.exampleIn(r.typ, contentType)
+	if err != nil {
+		return nil, err
+	}
+	response.Body = body
+	response.PreviewLanguage = previewLanguage(contentType)
+	for _, h := range response.Headers.Headers {
+		if h.Key == "Content-Type" {
+			h.Value = contentType
+		}
+	}
+	return response, nil
+}
+
+// acceptRequest returns a copy of request with its Accept header set to
+// contentType.
+func acceptRequest(request *postman.Request, contentType string) *postman.Request {
+	copied := *request
+	copied.Header = slices.DeleteFunc(slices.Clone(request.Header), func(h *postman.Header) bool {
+		return strings.EqualFold(h.Key, "Accept")
+	})
+	copied.Header = append(copied.Header, &postman.Header{Key: "Accept", Value: contentType})
+	return &copied
+}
+
+// exampleIn renders the example of t in contentType: JSON, XML or CSV.
+func (p *PostmanGen) exampleIn(t reflect.Type, contentType string) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch previewLanguage(mediaType) {
+	case "json":
+		return p.exampleJSON(t)
+	case "xml":
+		v := reflect.New(derefType(t))
+		p.fillXMLExample(v.Elem(), map[reflect.Type]bool{})
+		b, err := xml.MarshalIndent(v.Interface(), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal xml response body: %w", err)
+		}
+		return xml.Header + string(b), nil
+	}
+	if mediaType == "text/csv" {
+		return p.exampleCSV(t)
+	}
+	return "", fmt.Errorf("invalid produces %q: must be a JSON, XML or CSV content type", contentType)
+}
+
+// exampleCSV renders the example of t as CSV with a header row. Arrays of
+// objects give a row per element and objects a single row; nested values are
+// written as JSON.
+func (p *PostmanGen) exampleCSV(t reflect.Type) (string, error) {
+	b, err := p.encodeJSONBody(p.ExampleValue(t), t)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal csv response body: %w", err)
+	}
+	node, err := decodeOrdered(json.NewDecoder(bytes.NewReader(b)))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal csv response body: %w", err)
+	}
+
+	rows := []*orderedNode{node}
+	if node.kind == 'a' {
+		rows = node.values
+	}
+	columns := []string{}
+	for _, row := range rows {
+		for _, key := range row.keys {
+			if !slices.Contains(columns, key) {
+				columns = append(columns, key)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if len(columns) > 0 {
+		w.Write(columns)
+	}
+	for _, row := range rows {
+		record := []string{}
+		if row.kind != 'o' {
+			record = append(record, csvCell(row))
+		}
+		for _, column := range columns {
+			cell := ""
+			if i := slices.Index(row.keys, column); i >= 0 {
+				cell = csvCell(row.values[i])
+			}
+			record = append(record, cell)
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// csvCell returns the CSV cell of a JSON value.
+func csvCell(node *orderedNode) string {
+	if node.kind == 'v' {
+		if node.scalar == nil {
+			return ""
+		}
+		return fmt.Sprint(node.scalar)
+	}
+	buf := &bytes.Buffer{}
+	writeOrderedJSON(buf, node)
+	return buf.String()
+}
@@ -0,0 +1,84 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// CoverageReport lists the differences between the registered routes and an
+// OpenAPI document. Endpoints are written as "GET /users/{id}".
+type CoverageReport struct {
+	// Unregistered are the operations of the document without a registered
+	// route.
+	Unregistered []string
+	// NotInSpec are the registered routes the document does not describe.
+	NotInSpec []string
+}
+
+// Covered reports whether the routes and the document describe the same
+// endpoints.
+func (r CoverageReport) Covered() bool {
+	return len(r.Unregistered) == 0 && len(r.NotInSpec) == 0
+}
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// CompareWithOpenAPI reads an OpenAPI 3 or Swagger 2 document in JSON from r
+// and reports the operations that are not registered and the routes that are
+// not in the document. Paths match regardless of parameter names and syntax,
+// so /users/:userId matches /users/{id}.
+func (p *PostmanGen) CompareWithOpenAPI(r io.Reader) (CoverageReport, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return CoverageReport{}, fmt.Errorf("failed to read OpenAPI document: %w", err)
+	}
+
+	specified := map[string]string{}
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			if slices.Contains(openAPIMethods, method) {
+				endpoint := strings.ToUpper(method) + " " + path
+				specified[endpointKey(strings.ToUpper(method), path)] = endpoint
+			}
+		}
+	}
+
+	registered := map[string]string{}
+	for _, route := range p.routes {
+		method := strings.ToUpper(route.method)
+		registered[endpointKey(method, route.path)] = method + " " + route.path
+	}
+
+	report := CoverageReport{Unregistered: []string{}, NotInSpec: []string{}}
+	for key, endpoint := range specified {
+		if _, ok := registered[key]; !ok {
+			report.Unregistered = append(report.Unregistered, endpoint)
+		}
+	}
+	for key, endpoint := range registered {
+		if _, ok := specified[key]; !ok {
+			report.NotInSpec = append(report.NotInSpec, endpoint)
+		}
+	}
+	slices.Sort(report.Unregistered)
+	slices.Sort(report.NotInSpec)
+
+	return report, nil
+}
+
+// endpointKey returns method and path with parameter segments replaced by
+// {}, so paths differing only in parameter names and syntax are equal.
+func endpointKey(method string, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if _, _, ok := pathParamKey(segment); ok {
+			segments[i] = "{}"
+		}
+	}
+	return method + " /" + strings.Join(segments, "/")
+}
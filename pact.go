@@ -0,0 +1,140 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+type pactFile struct {
+	Consumer     pactParticipant   `json:"consumer"`
+	Provider     pactParticipant   `json:"provider"`
+	Interactions []pactInteraction `json:"interactions"`
+	Metadata     pactMetadata      `json:"metadata"`
+}
+
+type pactParticipant struct {
+	Name string `json:"name"`
+}
+
+type pactInteraction struct {
+	Description string       `json:"description"`
+	Request     pactRequest  `json:"request"`
+	Response    pactResponse `json:"response"`
+}
+
+type pactRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    any               `json:"body,omitempty"`
+}
+
+type pactResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    any               `json:"body,omitempty"`
+}
+
+type pactMetadata struct {
+	PactSpecification struct {
+		Version string `json:"version"`
+	} `json:"pactSpecification"`
+}
+
+// WritePact writes a Pact consumer contract (specification v2) with one
+// interaction per declared response of every registered route. Routes without
+// a response type are skipped, as a contract needs both sides.
+func (p *PostmanGen) WritePact(w io.Writer, consumer string, provider string) error {
+	pact := pactFile{
+		Consumer:     pactParticipant{Name: consumer},
+		Provider:     pactParticipant{Name: provider},
+		Interactions: []pactInteraction{},
+	}
+	pact.Metadata.PactSpecification.Version = "2.0.0"
+
+	for _, r := range p.routes {
+		for _, resp := range r.responses {
+			request := pactRequest{
+				Method:  strings.ToUpper(r.method),
+				Path:    p.resolveVariables(examplePath(r.request.URL)),
+				Query:   exampleQuery(p.enabledQuery(r.request.URL)),
+				Headers: headerMap(r.request.Header),
+			}
+			if text, ok := bodyText(r.request.Body); ok {
+				var body any
+				if err := json.Unmarshal([]byte(text), &body); err == nil {
+					request.Body = body
+				}
+			}
+
+			response := pactResponse{Status: resp.status, Headers: map[string]string{}}
+			if resp.typ != nil {
+				response.Headers["Content-Type"] = "application/json"
+				response.Body = p.ExampleValue(resp.typ)
+			}
+			maps.Copy(response.Headers, resp.headers)
+
+			pact.Interactions = append(pact.Interactions, pactInteraction{
+				Description: r.method + " " + r.path + " returns " + statusLabel(resp.status),
+				Request:     request,
+				Response:    response,
+			})
+		}
+	}
+
+	b, err := json.MarshalIndent(pact, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// examplePath returns the percent-encoded path of u with its path variables
+// replaced by their example values.
+func examplePath(u *postman.URL) string {
+	segments := make([]string, len(u.Path))
+	for i, segment := range u.Path {
+		segments[i] = segment
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		// Matrix parameters follow the variable, e.g. :id;color=red.
+		name, matrix, hasMatrix := strings.Cut(segment, ";")
+		if hasMatrix {
+			matrix = ";" + matrix
+		}
+		for _, v := range u.Variables {
+			if ":"+v.Key == name && v.Value != "" && v.Value != name {
+				segments[i] = v.Value + matrix
+			}
+		}
+	}
+	return escapePath(segments)
+}
+
+// exampleQuery returns the percent-encoded query string built from the example
+// values of query.
+func exampleQuery(query []*postman.QueryParam) string {
+	pairs := []string{}
+	for _, q := range query {
+		pairs = append(pairs, escapeQueryComponent(q.Key)+"="+escapeQueryComponent(q.Value))
+	}
+	return strings.Join(pairs, "&")
+}
+
+func headerMap(headers []*postman.Header) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	m := map[string]string{}
+	for _, h := range headers {
+		m[h.Key] = h.Value
+	}
+	return m
+}
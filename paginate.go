@@ -0,0 +1,64 @@
+package postmangen
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+)
+
+// paginationParams are the query fields RegisterPaginated adds to list
+// endpoints. The cursor is disabled because the first page is requested
+// without one.
+var paginationParams = []reflect.StructField{
+	{Name: "Page", Type: reflect.TypeOf(0), Tag: `query:"page" example:"1" description:"Page number, starting at 1"`},
+	{Name: "Limit", Type: reflect.TypeOf(0), Tag: `query:"limit" example:"20" description:"Maximum number of items per page"`},
+	{Name: "Cursor", Type: reflect.TypeOf(""), Tag: `query:"cursor" disabled:"true" description:"Cursor of the page to fetch, the next_cursor of the previous page"`},
+}
+
+// RegisterPaginated registers a list endpoint whose response is a page of
+// itemType values in the envelope {"items": [...], "total": 1,
+// "next_cursor": "..."}. The page, limit and cursor query parameters are added
+// to the input unless it already declares them. The spec is otherwise passed
+// to Register unchanged; its "responseType" is replaced by the envelope.
+func (p *PostmanGen) RegisterPaginated(spec RouteSpec, itemType reflect.Type) error {
+	inputType, ok := spec["inputType"].(reflect.Type)
+	if !ok {
+		return errors.New("invalid spec: must contain method, path, and inputType")
+	}
+	if itemType == nil {
+		return errors.New("invalid item type: must not be nil")
+	}
+	if derefType(inputType).Kind() != reflect.Struct {
+		return errors.New("invalid object type: must be a struct or pointer to struct")
+	}
+
+	declared := map[string]bool{}
+	walkStructFields(inputType, func(field reflect.StructField) {
+		if key := field.Tag.Get("query"); key != "" && key != "-" {
+			declared[key] = true
+		}
+	})
+
+	// The input is kept as an untagged field, which Register walks into like
+	// any nested struct.
+	fields := []reflect.StructField{{Name: "Input", Type: inputType}}
+	for _, param := range paginationParams {
+		if !declared[param.Tag.Get("query")] {
+			fields = append(fields, param)
+		}
+	}
+
+	paginated := maps.Clone(spec)
+	paginated["inputType"] = reflect.StructOf(fields)
+	paginated["responseType"] = paginatedResponseType(itemType)
+	return p.Register(paginated)
+}
+
+// paginatedResponseType returns the envelope of a page of itemType values.
+func paginatedResponseType(itemType reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Items", Type: reflect.SliceOf(itemType), Tag: `json:"items"`},
+		{Name: "Total", Type: reflect.TypeOf(0), Tag: `json:"total" example:"1" description:"Number of items across all pages"`},
+		{Name: "NextCursor", Type: reflect.TypeOf(""), Tag: `json:"next_cursor" example:"eyJwYWdlIjoyfQ" description:"Cursor of the next page, empty on the last page"`},
+	})
+}
@@ -0,0 +1,147 @@
+package postmangen
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// SetParallelism makes RegisterAll, and the functions registering through it
+// such as RegisterMap and RegisterRouteTable, generate up to workers routes
+// concurrently, for large registries. The collection is assembled in spec
+// order, so it is identical to a sequential generation. Placeholder functions
+// and the logger must then be safe for concurrent use. Values below 2, the
+// default, register sequentially.
+func (p *PostmanGen) SetParallelism(workers int) *PostmanGen {
+	p.parallelism = workers
+	return p
+}
+
+// registerParallel registers specs in forks of p, one per spec, and merges
+// them into p in spec order.
+func (p *PostmanGen) registerParallel(specs []RouteSpec) error {
+	forks := make([]*PostmanGen, len(specs))
+	results := make([]error, len(specs))
+	for i := range specs {
+		forks[i] = p.fork()
+	}
+
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for range min(p.parallelism, len(specs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = forks[i].Register(specs[i])
+			}
+		}()
+	}
+	for i := range specs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	errs := []error{}
+	for i, fork := range forks {
+		if results[i] != nil {
+			errs = append(errs, fmt.Errorf("%v %v: %w", specs[i]["method"], specs[i]["path"], results[i]))
+			continue
+		}
+		if err := p.merge(fork); err != nil {
+			errs = append(errs, fmt.Errorf("%v %v: %w", specs[i]["method"], specs[i]["path"], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fork returns a copy of p sharing its configuration, with an empty
+// collection and registration state, into which a route can be registered
+// independently of p and other forks.
+func (p *PostmanGen) fork() *PostmanGen {
+	forked := *p
+	collection := *p.collection
+	collection.Items = nil
+	collection.Variables = append([]*postman.Variable{}, p.collection.Variables...)
+	forked.collection = &collection
+	forked.routes = nil
+	forked.usedPlaceholders = map[string]bool{}
+	forked.dataColumns = nil
+	forked.dataRow = map[string]string{}
+	forked.disabledQuery = map[*postman.QueryParam]bool{}
+	forked.currentRoute, forked.routeExamples = nil, nil
+	return &forked
+}
+
+// merge adds what was registered in fork to p, as if it had been registered
+// in p. It fails, adding nothing, if the fork's items collide with p's
+// items, as selected by SetFolderCollision.
+func (p *PostmanGen) merge(fork *PostmanGen) error {
+	if p.folderCollision == FolderCollisionError {
+		err := eachRequestGroup(nil, fork.collection.Items, func(folderSegments []string, requests []*postman.Items) error {
+			return p.checkFolderCollision(folderSegments, requestName(requests))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	err := eachRequestGroup(nil, fork.collection.Items, func(folderSegments []string, requests []*postman.Items) error {
+		return p.addToFolder(folderSegments, requests...)
+	})
+	if err != nil {
+		return err
+	}
+	for _, v := range fork.collection.Variables {
+		if p.variableIndex(v.Key) < 0 {
+			p.collection.Variables = append(p.collection.Variables, v)
+		}
+	}
+	p.routes = append(p.routes, fork.routes...)
+	for key := range fork.usedPlaceholders {
+		p.usedPlaceholders[key] = true
+	}
+	for _, column := range fork.dataColumns {
+		p.recordDataValue(column, fork.dataRow[column])
+	}
+	for param := range fork.disabledQuery {
+		p.disabledQuery[param] = true
+	}
+	return nil
+}
+
+// eachRequestGroup calls fn with the requests of items, and of the folders
+// in items, and the folder at folderSegments they are in. Consecutive
+// requests, those of a route, are passed together.
+func eachRequestGroup(folderSegments []string, items []*postman.Items, fn func(folderSegments []string, requests []*postman.Items) error) error {
+	errs := []error{}
+	requests := []*postman.Items{}
+	flush := func() {
+		if len(requests) > 0 {
+			errs = append(errs, fn(folderSegments, requests))
+			requests = []*postman.Items{}
+		}
+	}
+	for _, item := range items {
+		if item.Request == nil && item.Items != nil {
+			flush()
+			errs = append(errs, eachRequestGroup(append(folderSegments[:len(folderSegments):len(folderSegments)], item.Name), item.Items, fn))
+			continue
+		}
+		requests = append(requests, item)
+	}
+	flush()
+	return errors.Join(errs...)
+}
+
+// variableIndex returns the index of the collection variable key, or -1.
+func (p *PostmanGen) variableIndex(key string) int {
+	for i, v := range p.collection.Variables {
+		if v.Key == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,143 @@
+package postmangen
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// PathParamStyle selects how path parameters appear in generated request URLs.
+type PathParamStyle int
+
+const (
+	// PathVariables emits :id segments backed by per-request path variables,
+	// which Postman lists in the request's Path Variables table.
+	PathVariables PathParamStyle = iota
+	// CollectionVariables emits {{id}} segments resolved from collection
+	// variables, so one value is shared by every request using the parameter.
+	CollectionVariables
+)
+
+// SetPathParamStyle selects how path parameters are written to request URLs.
+// Route paths may declare parameters as :id, {id} or <id> regardless of the
+// style. The default is PathVariables.
+func (p *PostmanGen) SetPathParamStyle(style PathParamStyle) *PostmanGen {
+	p.pathParamStyle = style
+	return p
+}
+
+// pathParamAsVariable reports whether the path parameter at index i of a
+// route's n path segments is written as a {{key}} collection variable rather
+// than a :key path variable. Postman reads a path variable up to the next
+// slash, so matrix parameters after :id would become part of its name; the
+// last segment of a route with matrix parameters is then a variable too.
+func (p *PostmanGen) pathParamAsVariable(i int, n int, matrix bool) bool {
+	return p.pathParamStyle == CollectionVariables || matrix && i == n-1
+}
+
+// routeSegments returns the path segments of a route as Register writes
+// them, with parameters as :key or {{key}}.
+func (p *PostmanGen) routeSegments(path string, matrix bool) []string {
+	path, _, _ = strings.Cut(path, "#")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range pathSegments {
+		if key, _, ok := pathParamKey(segment); ok {
+			if p.pathParamAsVariable(i, len(pathSegments), matrix) {
+				pathSegments[i] = "{{" + key + "}}"
+			} else {
+				pathSegments[i] = ":" + key
+			}
+		}
+	}
+	return pathSegments
+}
+
+// hasMatrixParams reports whether a request struct has matrix-tagged fields.
+func hasMatrixParams(t reflect.Type) bool {
+	found := false
+	walkStructFields(t, func(field reflect.StructField) {
+		if tag := field.Tag.Get("matrix"); tag != "" && tag != "-" {
+			found = true
+		}
+	})
+	return found
+}
+
+// catchAllDescription documents path variables translated from wildcards.
+const catchAllDescription = "Catch-all: matches the rest of the path, including slashes."
+
+// catchAllKey returns the parameter name of a wildcard segment written as
+// *name, {name...} or a bare *, which is named "path".
+func catchAllKey(segment string) (string, bool) {
+	switch {
+	case segment == "*":
+		return "path", true
+	case strings.HasPrefix(segment, "*"):
+		return segment[1:], true
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}") && len(segment) > 5:
+		return segment[1 : len(segment)-4], true
+	}
+	return "", false
+}
+
+// pathParamKey returns the parameter name of a path segment written as :id,
+// {id}, {id:regex} or <id>, and false for literal segments. Wildcards are
+// reported with catchAll set.
+func pathParamKey(segment string) (key string, catchAll bool, ok bool) {
+	if key, ok := catchAllKey(segment); ok {
+		return key, true, true
+	}
+
+	switch {
+	case strings.HasPrefix(segment, ":") && len(segment) > 1:
+		return segment[1:], false, true
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && len(segment) > 2 && !strings.HasPrefix(segment, "{{"):
+		key, _, _ := strings.Cut(segment[1:len(segment)-1], ":")
+		return key, false, true
+	case strings.HasPrefix(segment, "<") && strings.HasSuffix(segment, ">") && len(segment) > 2:
+		return segment[1 : len(segment)-1], false, true
+	}
+	return "", false, false
+}
+
+// pathParamConstraint returns the regular expression constraining a
+// parameter written as {id:[0-9]+}, as chi and gorilla/mux support, or an
+// empty string.
+func pathParamConstraint(segment string) string {
+	if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") || strings.HasPrefix(segment, "{{") {
+		return ""
+	}
+	_, pattern, _ := strings.Cut(segment[1:len(segment)-1], ":")
+	return pattern
+}
+
+// anchoredPattern returns pattern matching whole path segments only.
+func anchoredPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// addPathParamVariable defines the collection variable backing a {{key}} path
+// segment, or another variable generated requests refer to. Variables that
+// already exist, e.g. from AddVariable or an earlier route, are left
+// unchanged. During Register the variable is kept with the route's pending
+// changes.
+func (p *PostmanGen) addPathParamVariable(key string, value string, description string) {
+	if p.variableIndex(key) >= 0 {
+		return
+	}
+	variables := &p.collection.Variables
+	if p.pending != nil {
+		if slices.ContainsFunc(p.pending.variables, func(v *postman.Variable) bool { return v.Key == key }) {
+			return
+		}
+		variables = &p.pending.variables
+	}
+	*variables = append(*variables, &postman.Variable{
+		Key:         key,
+		Type:        "string",
+		Value:       value,
+		Description: description,
+	})
+}
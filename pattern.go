@@ -0,0 +1,118 @@
+package postmangen
+
+import (
+	"reflect"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// patternDescription appends pattern to the description of a field.
+func patternDescription(description string, pattern string) string {
+	return strings.TrimSpace(description + " Pattern: " + pattern)
+}
+
+// patternExample returns an example for a string field with a pattern, or
+// false if the field is not a string or no example could be derived.
+func patternExample(t reflect.Type, pattern string) (string, bool) {
+	if pattern == "" || derefType(t).Kind() != reflect.String {
+		return "", false
+	}
+	return regexExample(pattern)
+}
+
+// regexExample returns a short string matching pattern, taking the first
+// alternative and the minimum number of repetitions. It returns false for
+// invalid patterns and for those the result does not match, e.g. because of
+// lookaround-like constructs spelled with word boundaries.
+func regexExample(pattern string) (string, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	b := &strings.Builder{}
+	writeRegexExample(b, parsed.Simplify())
+	example := b.String()
+	if !re.MatchString(example) {
+		return "", false
+	}
+	return example, true
+}
+
+func writeRegexExample(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(charClassExample(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture:
+		writeRegexExample(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRegexExample(b, sub)
+		}
+	case syntax.OpAlternate:
+		writeRegexExample(b, re.Sub[0])
+	case syntax.OpPlus:
+		writeRegexExample(b, re.Sub[0])
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			writeRegexExample(b, re.Sub[0])
+		}
+	}
+	// Stars, optional parts, anchors and empty matches contribute nothing.
+}
+
+// charClassExample returns a readable rune of a character class given as
+// ranges of lo, hi pairs, preferring letters and digits.
+func charClassExample(ranges []rune) rune {
+	contains := func(r rune) bool {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return true
+			}
+		}
+		return false
+	}
+	for _, r := range "abcxyzABCXYZ0123456789-_." {
+		if contains(r) {
+			return r
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r-ranges[i] < 256; r++ {
+			if unicode.IsPrint(r) && r != ' ' {
+				return r
+			}
+		}
+	}
+	if len(ranges) > 0 {
+		return ranges[0]
+	}
+	return 'a'
+}
+
+// patternMismatch returns a string that does not match pattern, or false if
+// pattern is empty or invalid or matches all candidates.
+func patternMismatch(pattern string) (string, bool) {
+	re, err := regexp.Compile(pattern)
+	if pattern == "" || err != nil {
+		return "", false
+	}
+	for _, candidate := range []string{"!invalid!", "", "0", "a", " "} {
+		if !re.MatchString(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
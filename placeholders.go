@@ -0,0 +1,98 @@
+package postmangen
+
+import (
+	"reflect"
+	"strings"
+)
+
+// RouteInfo describes the route being registered to placeholder functions.
+type RouteInfo struct {
+	Method string
+	Path   string
+	// Name is the request name and Folder the folder path it is placed in,
+	// e.g. "billing/invoices", empty at the top level.
+	Name   string
+	Folder string
+}
+
+// PlaceholderFunc returns the example of a field, see AddPlaceholderFunc.
+type PlaceholderFunc func(field reflect.StructField, route RouteInfo) string
+
+// AddPlaceholderForType sets the default example of every field of type t
+// without an example tag or a placeholder for its name, e.g.
+// AddPlaceholderForType(reflect.TypeOf(time.Time{}), "2024-01-01T00:00:00Z").
+// Pointer fields use the placeholder of the type they point to.
+func (p *PostmanGen) AddPlaceholderForType(t reflect.Type, value string) *PostmanGen {
+	p.typePlaceholders[t] = value
+	return p
+}
+
+// typePlaceholder returns the placeholder added for t or, for pointers, the
+// type it points to.
+func (p *PostmanGen) typePlaceholder(t reflect.Type) (string, bool) {
+	if value, ok := p.typePlaceholders[t]; ok {
+		return value, true
+	}
+	value, ok := p.typePlaceholders[derefType(t)]
+	return value, ok
+}
+
+// AddPlaceholderFunc sets a function computing the default example of fields
+// named key, for values depending on the route or the field, e.g. a tenant ID
+// per folder:
+//
+//	pg.AddPlaceholderFunc("tenant_id", func(field reflect.StructField, route postmangen.RouteInfo) string {
+//		return tenants[route.Folder]
+//	})
+//
+// It takes precedence over a placeholder added with AddPlaceholder for the
+// same key, which is used when it returns an empty string. Examples rendered
+// outside of Register, e.g. by ExampleValue, get the zero RouteInfo.
+func (p *PostmanGen) AddPlaceholderFunc(key string, fn PlaceholderFunc) *PostmanGen {
+	p.placeholderFuncs[key] = fn
+	return p
+}
+
+// namedPlaceholder returns the placeholder of field for the first of keys
+// with one, and that key.
+func (p *PostmanGen) namedPlaceholder(field reflect.StructField, keys ...string) (string, string, bool) {
+	for _, key := range keys {
+		if fn, ok := p.placeholderFuncs[key]; ok {
+			route := RouteInfo{}
+			if p.currentRoute != nil {
+				route = *p.currentRoute
+			}
+			if value := fn(field, route); value != "" {
+				p.usedPlaceholders[key] = true
+				return value, key, true
+			}
+		}
+		if value, ok := p.placeholderDefaults[key]; ok {
+			p.usedPlaceholders[key] = true
+			return value, key, true
+		}
+	}
+	return "", "", false
+}
+
+// routeInfo describes the route of spec with the given method and path, whose
+// parameter segments are written in the configured path parameter style.
+func (p *PostmanGen) routeInfo(spec map[string]any, method string, path string) RouteInfo {
+	path, _, _ = strings.Cut(path, "#")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range pathSegments {
+		if key, _, ok := pathParamKey(segment); ok {
+			pathSegments[i] = ":" + key
+			if p.pathParamStyle == CollectionVariables {
+				pathSegments[i] = "{{" + key + "}}"
+			}
+		}
+	}
+
+	return RouteInfo{
+		Method: method,
+		Path:   path,
+		Name:   p.routeName(spec, pathSegments),
+		Folder: strings.Join(p.routeFolder(spec, pathSegments), "/"),
+	}
+}
@@ -0,0 +1,37 @@
+package postmangen
+
+import "reflect"
+
+// PointerStyle selects how pointer fields without an example or placeholder
+// appear in example JSON bodies.
+type PointerStyle int
+
+const (
+	// PointerExample renders the example of the pointed-to type, as if the
+	// field were not a pointer.
+	PointerExample PointerStyle = iota
+	// PointerNull renders null, the value of a nil pointer.
+	PointerNull
+	// PointerOmit leaves the field out, showing that it is optional.
+	PointerOmit
+)
+
+// SetPointerStyle selects how pointer fields without an example tag or
+// placeholder are rendered in request and response bodies. Fields with an
+// example are always rendered with it. The default is PointerExample.
+func (p *PostmanGen) SetPointerStyle(style PointerStyle) *PostmanGen {
+	p.pointerStyle = style
+	return p
+}
+
+// pointerOmitted reports whether a field of type t without an example is left
+// out of example bodies.
+func (p *PostmanGen) pointerOmitted(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && p.pointerStyle == PointerOmit
+}
+
+// pointerNull reports whether a field of type t without an example is
+// rendered as null.
+func (p *PostmanGen) pointerNull(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && p.pointerStyle == PointerNull
+}
@@ -0,0 +1,34 @@
+package postmangen
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers are left to the garbage
+// collector rather than pooled, so a single large example does not pin its
+// memory.
+const maxPooledBuffer = 64 << 10
+
+// bufferPool holds the scratch buffers of example encoding, which is done for
+// every body and saved response, so generating many routes reuses them
+// instead of growing new ones.
+var bufferPool = sync.Pool{
+	New: func() any {
+		return &bytes.Buffer{}
+	},
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to the pool. Its content must no longer be used.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(buf)
+	}
+}
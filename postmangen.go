@@ -1,26 +1,175 @@
 package postmangen
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"maps"
+	"net/http"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/rbretecher/go-postman-collection"
 )
 
 type PostmanGen struct {
-	collection          *postman.Collection
-	placeholderDefaults map[string]string
+	collection           *postman.Collection
+	placeholderDefaults  map[string]string
+	typePlaceholders     map[reflect.Type]string
+	placeholderFuncs     map[string]PlaceholderFunc
+	dynamicVariables     bool
+	scriptFS             fs.FS
+	scriptData           map[string]any
+	scriptFiles          []scriptFile
+	scriptTemplates      map[string]string
+	docComments          map[string]string
+	maxResponseTimeMs    int
+	dataDriven           bool
+	dataColumns          []string
+	dataRow              map[string]string
+	negativeTests        bool
+	boundaryExamples     bool
+	routes               []*route
+	usedPlaceholders     map[string]bool
+	baseURL              BaseURL
+	folderBaseURLs       map[string]string
+	baseURLVariable      string
+	pathParamStyle       PathParamStyle
+	queryArrayStyle      QueryArrayStyle
+	disableOptionalQuery bool
+	disabledQuery        map[*postman.QueryParam]bool
+	protocolProfile      map[string]bool
+	clientCertificates   []ClientCertificate
+	sensitiveVariables   map[string]bool
+	logger               Logger
+	bodyIndent           int
+	preserveFieldOrder   bool
+	int64AsString        bool
+	pointerStyle         PointerStyle
+	namingStrategy       NamingStrategy
+	maxExampleDepth      int
+	maxExampleBytes      int
+	locale               string
+	language             string
+	translations         map[string]map[string]string
+	changelog            []changelogEntry
+	responseEnvelope     reflect.Type
+	envelopeKey          string
+	sanitizers           []Sanitizer
+	folderRateLimits     map[string]RateLimit
+	sessionAuth          *SessionAuth
+	allowedMethods       []string
+	headAndOptions       bool
+	namedTypes           map[string]reflect.Type
+	commonQueries        map[string]reflect.Type
+	keepTrailingSlash    bool
+	collapseSlashes      bool
+	parallelism          int
+	syncStateFile        string
+	gettingStarted       bool
+	folderMatching       FolderMatching
+	folderCollision      FolderCollision
+	folderStrategy       FolderStrategy
+	rootName             string
+	// teardown is the folder of RegisterTeardown, kept last in the
+	// collection.
+	teardown *postman.Items
+	// currentRoute is the route being registered, passed to placeholder
+	// functions, and routeExamples the examples of its "examples" spec key.
+	currentRoute  *RouteInfo
+	routeExamples map[string]any
+	// pending are the changes of the route being registered, applied once
+	// it is registered.
+	pending *routeChanges
+	// renderMu serializes render, which changes the collection temporarily,
+	// for Handler serving concurrent requests.
+	// It is shared with the forks of parallel registration.
+	renderMu *sync.Mutex
 }
 
-func NewPostmanGen(name string, description string) *PostmanGen {
+// route is a registered endpoint, kept for the exporters that need more than
+// the generated Postman item.
+type route struct {
+	method    string
+	path      string
+	name      string
+	inputType reflect.Type
+	responses []routeResponse
+	request   *postman.Request
+	// item is the generated Postman item, which recorded examples are added
+	// to, and items all the items generated for the route.
+	item  *postman.Items
+	items []*postman.Items
+
+	description     string
+	missingExamples []string
+	// baseVariable is the base URL variable of the route, empty when it uses
+	// the collection's base URL.
+	baseVariable string
+	// stream is set for Server-Sent Events endpoints.
+	stream bool
+}
+
+// formParam is an entry of a formdata request body.
+type formParam struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type"` // text | file
+	Description string `json:"description"`
+}
+
+// formFields returns the entries of a formdata body built by Register.
+func formFields(formData any) []formParam {
+	fields, _ := formData.([]formParam)
+	return fields
+}
+
+// routeResponse is a response type declared for a route.
+type routeResponse struct {
+	status  int
+	typ     reflect.Type
+	headers map[string]string
+}
+
+// Option configures a PostmanGen created by NewPostmanGen.
+type Option func(*PostmanGen)
+
+// WithBaseURLVariable sets the name of the collection variable request URLs
+// start with, "base_url" by default, e.g. WithBaseURLVariable("host") emits
+// {{host}}/users.
+func WithBaseURLVariable(name string) Option {
+	return func(p *PostmanGen) {
+		p.baseURLVariable = name
+	}
+}
+
+func NewPostmanGen(name string, description string, opts ...Option) *PostmanGen {
 	p := &PostmanGen{
 		collection:          postman.CreateCollection(name, description),
 		placeholderDefaults: map[string]string{},
+		typePlaceholders:    map[reflect.Type]string{},
+		placeholderFuncs:    map[string]PlaceholderFunc{},
+		scriptTemplates:     map[string]string{},
+		docComments:         map[string]string{},
+		renderMu:            &sync.Mutex{},
+		dataRow:             map[string]string{},
+		usedPlaceholders:    map[string]bool{},
+		folderBaseURLs:      map[string]string{},
+		folderRateLimits:    map[string]RateLimit{},
+		namedTypes:          map[string]reflect.Type{},
+		commonQueries:       map[string]reflect.Type{},
+		baseURLVariable:     "base_url",
+		disabledQuery:       map[*postman.QueryParam]bool{},
+		protocolProfile:     map[string]bool{},
+		sensitiveVariables:  map[string]bool{},
+		bodyIndent:          2,
+		translations:        map[string]map[string]string{},
 	}
 	p.collection.Auth = postman.CreateAuth(postman.Bearer, &postman.AuthParam{
 		Key:   "token",
@@ -28,6 +177,10 @@ func NewPostmanGen(name string, description string) *PostmanGen {
 		Type:  "string",
 	})
 
+	for _, opt := range opts {
+		opt(p)
+	}
+
 	return p
 }
 
@@ -36,10 +189,19 @@ func hasAnyRelevantTag(f reflect.StructField) bool {
 		(f.Tag.Get("form") != "" && f.Tag.Get("form") != "-") ||
 		(f.Tag.Get("formFile") != "" && f.Tag.Get("formFile") != "-") ||
 		(f.Tag.Get("query") != "" && f.Tag.Get("query") != "-") ||
-		(f.Tag.Get("param") != "" && f.Tag.Get("param") != "-")
+		(f.Tag.Get("param") != "" && f.Tag.Get("param") != "-") ||
+		(f.Tag.Get("matrix") != "" && f.Tag.Get("matrix") != "-")
 }
 
 func walkStructFields(t reflect.Type, fn func(field reflect.StructField)) {
+	walkOwnedStructFields(t, func(owner reflect.Type, field reflect.StructField) {
+		fn(field)
+	})
+}
+
+// walkOwnedStructFields is walkStructFields also passing the struct type
+// declaring each field.
+func walkOwnedStructFields(t reflect.Type, fn func(owner reflect.Type, field reflect.StructField)) {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -59,26 +221,37 @@ func walkStructFields(t reflect.Type, fn func(field reflect.StructField)) {
 			ft = ft.Elem()
 		}
 
-		if f.Anonymous && ft.Kind() == reflect.Struct {
-			walkStructFields(ft, fn)
+		// Like encoding/json, embedded structs are flattened unless their json
+		// tag names them, which nests them under that name, or is "-", which
+		// leaves them out.
+		jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && jsonName == "-" {
+			continue
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct && jsonName == "" {
+			walkOwnedStructFields(ft, fn)
 			continue
 		}
 
 		if !f.Anonymous && ft.Kind() == reflect.Struct && !hasAnyRelevantTag(f) {
-			walkStructFields(ft, fn)
+			walkOwnedStructFields(ft, fn)
 			continue
 		}
 
-		fn(f)
+		fn(t, f)
 	}
 }
 
-func (p *PostmanGen) AddVariable(key string, value string) *PostmanGen {
-	p.collection.Variables = append(p.collection.Variables, &postman.Variable{
-		Key:   key,
-		Type:  "string",
-		Value: value,
-	})
+// AddVariable adds a collection variable whose Postman type is inferred from
+// value: "string", "boolean", "number", or "any" for other values, which are
+// written as JSON. Options can disable or describe it, e.g.
+// AddVariable("legacy_url", v, WithDisabled(), WithDescription("old gateway")).
+func (p *PostmanGen) AddVariable(key string, value any, opts ...VariableOption) *PostmanGen {
+	v := collectionVariable(key, value)
+	for _, opt := range opts {
+		opt(v)
+	}
+	p.collection.Variables = append(p.collection.Variables, v)
 	return p
 }
 
@@ -87,7 +260,15 @@ func (p *PostmanGen) AddPlaceholder(key string, value string) *PostmanGen {
 	return p
 }
 
-func (p *PostmanGen) Register(spec map[string]any) error {
+// SetMaxResponseTime adds a test asserting that the response time is below ms
+// milliseconds to every registered route. Routes can override it with the
+// "maxResponseTimeMs" spec key. Zero disables the assertion.
+func (p *PostmanGen) SetMaxResponseTime(ms int) *PostmanGen {
+	p.maxResponseTimeMs = ms
+	return p
+}
+
+func (p *PostmanGen) Register(spec RouteSpec) error {
 	defer func() {
 		recover()
 	}()
@@ -99,6 +280,9 @@ func (p *PostmanGen) Register(spec map[string]any) error {
 	if !ok1 || !ok2 || !ok3 {
 		return errors.New("invalid spec: must contain method, path, and inputType")
 	}
+	if err := p.validateMethod(method); err != nil {
+		return err
+	}
 
 	typ := inputType
 	if typ.Kind() == reflect.Ptr {
@@ -108,122 +292,240 @@ func (p *PostmanGen) Register(spec map[string]any) error {
 	if typ.Kind() != reflect.Struct {
 		return errors.New("invalid object type: must be a struct or pointer to struct")
 	}
+	if groups := specCommonQuery(spec); len(groups) > 0 && !isProtoMessage(typ) {
+		withQuery, err := p.withCommonQuery(inputType, groups)
+		if err != nil {
+			return err
+		}
+		inputType, typ = withQuery, withQuery
+	}
 
-	type FormParam struct {
-		Key         string `json:"key"`
-		Value       string `json:"value"`
-		Type        string `json:"type"` // text | file
-		Description string `json:"description"`
+	path = p.normalizePath(path)
+	info := p.routeInfo(spec, method, path)
+	p.currentRoute, p.routeExamples = &info, specExamples(spec)
+	p.pending = newRouteChanges()
+	defer func() {
+		p.currentRoute, p.routeExamples = nil, nil
+		p.pending = nil
+	}()
+
+	// A collision is reported before the fields are walked, which records
+	// data columns, disabled query params and path variables.
+	if p.folderCollision == FolderCollisionError {
+		segments := p.routeSegments(path, !isProtoMessage(typ) && hasMatrixParams(typ))
+		if err := p.checkFolderCollision(p.routeFolder(spec, segments), p.routeName(spec, segments)); err != nil {
+			return err
+		}
 	}
 
 	jsonParams := map[string]any{}
-	formParams := []FormParam{}
+	unquotedRefs := []string{}
+	jsonFields := []jsonBodyField{}
+	formParams := []formParam{}
 	queryParams := []*postman.QueryParam{}
 	pathVariables := []*postman.Variable{}
+	matrixParams := []matrixParam{}
+	missingExamples := []string{}
 
-	walkStructFields(typ, func(field reflect.StructField) {
-		fieldName := field.Name
-		jsonTag := field.Tag.Get("json")
-		formTag := field.Tag.Get("form")
-		formFileTag := field.Tag.Get("formFile")
-		queryTag := field.Tag.Get("query")
-		paramTag := field.Tag.Get("param")
-		description := field.Tag.Get("description")
-		example := field.Tag.Get("example")
-
-		jsonKey := jsonTag
-		if jsonKey == "" || jsonKey == "-" {
-			jsonKey = fieldName
-		}
-		formKey := formTag
-		if formKey == "" || formKey == "-" {
-			formKey = fieldName
-		}
-		formFileKey := formFileTag
-		if formFileKey == "" || formFileKey == "-" {
-			formFileKey = fieldName
-		}
-		queryKey := queryTag
-		if queryKey == "" || queryKey == "-" {
-			queryKey = fieldName
-		}
-		paramKey := paramTag
-		if paramKey == "" || paramKey == "-" {
-			paramKey = fieldName
-		}
-
-		var placeholderValue any = example
-		if placeholderValue == "" || placeholderValue == "-" {
-			if defaultValue, ok := p.placeholderDefaults[jsonKey]; ok {
-				placeholderValue = defaultValue
-			} else if defaultValue, ok := p.placeholderDefaults[formKey]; ok {
-				placeholderValue = defaultValue
-			} else if defaultValue, ok := p.placeholderDefaults[formFileKey]; ok {
-				placeholderValue = defaultValue
-			} else if defaultValue, ok := p.placeholderDefaults[queryKey]; ok {
-				placeholderValue = defaultValue
-			} else if defaultValue, ok := p.placeholderDefaults[paramKey]; ok {
-				placeholderValue = defaultValue
-			}
-		}
-
-		if jsonTag != "" && jsonTag != "-" {
-			value := placeholderValue
+	if isProtoMessage(typ) {
+		// Generated messages are mapped by their protobuf tags rather than the
+		// json tags protoc-gen-go also emits.
+		jsonParams, queryParams, pathVariables = p.protoInputFields(typ, method, path)
+	} else {
+		walkOwnedStructFields(typ, func(owner reflect.Type, field reflect.StructField) {
+			fieldName := field.Name
+			jsonTag := field.Tag.Get("json")
+			formTag := field.Tag.Get("form")
+			formFileTag := field.Tag.Get("formFile")
+			queryTag := field.Tag.Get("query")
+			paramTag := field.Tag.Get("param")
+			description := p.fieldDescription(owner, field)
+			example := field.Tag.Get("example")
+
+			jsonKey, _, _ := strings.Cut(jsonTag, ",")
+			if jsonKey == "" || jsonKey == "-" {
+				jsonKey = p.untaggedName(fieldName)
+			}
+			formKey := formTag
+			if formKey == "" || formKey == "-" {
+				formKey = fieldName
+			}
+			formFileKey := formFileTag
+			if formFileKey == "" || formFileKey == "-" {
+				formFileKey = fieldName
+			}
+			queryKey := queryTag
+			if queryKey == "" || queryKey == "-" {
+				queryKey = fieldName
+			}
+			paramKey := paramTag
+			if paramKey == "" || paramKey == "-" {
+				paramKey = fieldName
+			}
+
+			var placeholderValue any = example
+			exampleSource := "example tag"
+			override, overridden := p.routeExample(jsonKey, formKey, formFileKey, queryKey, paramKey)
+			if overridden {
+				placeholderValue = override
+				exampleSource = "route examples"
+			}
+			if placeholderValue == "" || placeholderValue == "-" {
+				if defaultValue, key, ok := p.namedPlaceholder(field, jsonKey, formKey, formFileKey, queryKey, paramKey); ok {
+					placeholderValue = defaultValue
+					exampleSource = fmt.Sprintf("placeholder %q", key)
+				}
+			}
 			if placeholderValue == "" || placeholderValue == "-" {
-				value = p.TypeZeroValue(field.Type, false)
+				if defaultValue, ok := p.typePlaceholder(field.Type); ok {
+					placeholderValue = defaultValue
+					exampleSource = fmt.Sprintf("placeholder for %s", field.Type)
+				}
 			}
-			jsonParams[jsonKey] = value
-		}
+			dynamic, isDynamic := p.dynamicVariable(field, placeholderValue != "" && placeholderValue != "-", jsonKey, formKey, queryKey, paramKey)
+			isDynamic = isDynamic && !overridden
+			if isDynamic {
+				placeholderValue = dynamic
+				exampleSource = "dynamic variable"
+			}
+			if pattern := fieldValidateRules(field).Pattern; pattern != "" {
+				description = patternDescription(description, pattern)
+				if placeholderValue == "" || placeholderValue == "-" {
+					if generated, ok := patternExample(field.Type, pattern); ok {
+						placeholderValue = generated
+						exampleSource = "pattern"
+					}
+				}
+			}
+			if placeholderValue == "" || placeholderValue == "-" {
+				if generated, ok := p.localeExample(field.Type, jsonKey, formKey, queryKey, paramKey); ok {
+					placeholderValue = generated
+					exampleSource = "locale " + p.locale
+				}
+			}
+			if placeholderValue == "" || placeholderValue == "-" {
+				missingExamples = append(missingExamples, fieldName)
+				exampleSource = "zero value"
+			}
+			p.logField(method, path, field, placeholderValue, exampleSource)
 
-		if placeholderValue == "" || placeholderValue == "-" {
-			placeholderValue = p.TypeZeroValue(field.Type, true)
-		}
+			hasExample := placeholderValue != "" && placeholderValue != "-"
+			if jsonTag != "" && jsonTag != "-" && (hasExample || !p.pointerOmitted(field.Type)) {
+				value := placeholderValue
+				if !hasExample {
+					value = p.TypeZeroValue(field.Type, false)
+					if p.pointerNull(field.Type) {
+						value = nil
+					}
+				}
+				if value != nil && p.encodedAsString(field) {
+					value = stringEncoded(value, field.Type)
+				}
+				if isDynamic {
+					// The reference is unquoted for Postman to substitute a
+					// number or boolean.
+					if kind := derefType(field.Type).Kind(); (isNumberKind(kind) || kind == reflect.Bool) && !p.encodedAsString(field) {
+						unquotedRefs = append(unquotedRefs, dynamic)
+					}
+				} else {
+					text, quoted := jsonDataText(value)
+					p.recordDataValue(jsonKey, text)
+					if p.dataDriven {
+						value = dataReference(jsonKey)
+						if !quoted {
+							unquotedRefs = append(unquotedRefs, value.(string))
+						}
+					}
+				}
+				jsonParams[jsonKey] = value
+				jsonFields = append(jsonFields, jsonBodyField{key: jsonKey, field: field})
+			}
 
-		if formTag != "" && formTag != "-" {
-			formParams = append(formParams, FormParam{
-				Key:         formKey,
-				Value:       fmt.Sprint(placeholderValue),
-				Type:        "text",
-				Description: description,
-			})
-		}
+			if placeholderValue == "" || placeholderValue == "-" {
+				placeholderValue = p.TypeZeroValue(field.Type, true)
+			}
 
-		if formFileTag != "" && formFileTag != "-" {
-			formParams = append(formParams, FormParam{
-				Key:         formFileKey,
-				Value:       fmt.Sprint(placeholderValue),
-				Type:        "file",
-				Description: description,
-			})
-		}
+			// stringValue returns the value used for key in the form, query and
+			// path parts of the request, which are plain strings in Postman.
+			stringValue := func(key string) string {
+				if isDynamic {
+					return dynamic
+				}
+				p.recordDataValue(key, fmt.Sprint(placeholderValue))
+				if p.dataDriven {
+					return dataReference(key)
+				}
+				return fmt.Sprint(placeholderValue)
+			}
 
-		if queryTag != "" && queryTag != "-" {
-			queryParams = append(queryParams, &postman.QueryParam{
-				Key:         queryKey,
-				Value:       fmt.Sprint(placeholderValue),
-				Description: &description,
-			})
-		}
+			if formTag != "" && formTag != "-" {
+				formParams = append(formParams, formParam{
+					Key:         formKey,
+					Value:       stringValue(formKey),
+					Type:        "text",
+					Description: description,
+				})
+			}
 
-		if paramTag != "" && paramTag != "-" {
-			pathVariables = append(pathVariables, &postman.Variable{
-				Key:   paramKey,
-				Value: fmt.Sprint(placeholderValue),
-				Type:  "string",
-			})
-		}
-	})
+			if formFileTag != "" && formFileTag != "-" {
+				formParams = append(formParams, formParam{
+					Key:         formFileKey,
+					Value:       fmt.Sprint(placeholderValue),
+					Type:        "file",
+					Description: description,
+				})
+			}
+
+			if queryTag != "" && queryTag != "-" {
+				params := []*postman.QueryParam{}
+				if isQueryArray(field.Type) {
+					params = p.queryArrayParams(queryKey, placeholderValue, description)
+				} else {
+					params = append(params, &postman.QueryParam{
+						Key:         queryKey,
+						Value:       stringValue(queryKey),
+						Description: &description,
+					})
+				}
+				if p.queryDisabled(field) {
+					for _, param := range params {
+						p.disableQuery(param)
+					}
+				}
+				queryParams = append(queryParams, params...)
+			}
+
+			if paramTag != "" && paramTag != "-" {
+				pathVariables = append(pathVariables, &postman.Variable{
+					Key:         paramKey,
+					Value:       stringValue(paramKey),
+					Type:        "string",
+					Description: description,
+				})
+			}
+
+			if matrixTag := field.Tag.Get("matrix"); matrixTag != "" && matrixTag != "-" {
+				matrixParams = append(matrixParams, matrixParam{
+					key:         matrixTag,
+					value:       stringValue(matrixTag),
+					description: description,
+				})
+			}
+		})
+	}
 
-	processedPath := path
-	pathSegments := strings.Split(strings.Trim(processedPath, "/"), "/")
+	queryParams = append(queryParams, staticQueryParams(spec, queryParams)...)
+
+	path, fragment, _ := strings.Cut(path, "#")
+	if specFragment, ok := spec["fragment"].(string); ok {
+		fragment = strings.TrimPrefix(specFragment, "#")
+	}
+
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
 	urlVariables := []*postman.Variable{}
 
 	for i, segment := range pathSegments {
-		if strings.HasPrefix(segment, ":") {
-			key := strings.TrimPrefix(segment, ":")
-			// pathSegments[i] = "{{" + key + "}}"
-			pathSegments[i] = ":" + key
-
+		if key, catchAll, ok := pathParamKey(segment); ok {
 			defaultValue := ":" + key
 			description := ""
 			for _, pv := range pathVariables {
@@ -233,7 +535,25 @@ func (p *PostmanGen) Register(spec map[string]any) error {
 					break
 				}
 			}
+			if catchAll {
+				description = strings.TrimSpace(catchAllDescription + " " + description)
+			}
+			if pattern := pathParamConstraint(segment); pattern != "" {
+				description = patternDescription(description, pattern)
+				if defaultValue == ":"+key || defaultValue == "" {
+					if example, ok := regexExample(anchoredPattern(pattern)); ok {
+						defaultValue = example
+					}
+				}
+			}
 
+			if p.pathParamAsVariable(i, len(pathSegments), len(matrixParams) > 0) {
+				pathSegments[i] = "{{" + key + "}}"
+				p.addPathParamVariable(key, defaultValue, description)
+				continue
+			}
+
+			pathSegments[i] = ":" + key
 			urlVariables = append(urlVariables, &postman.Variable{
 				Key:         key,
 				Value:       defaultValue,
@@ -242,63 +562,266 @@ func (p *PostmanGen) Register(spec map[string]any) error {
 			})
 		}
 	}
-	processedPath = "/" + strings.Join(pathSegments, "/")
 
+	baseVariable := p.routeBaseURLVariable(spec, pathSegments)
+
+	urlSegments := withMatrixParams(pathSegments, matrixParams)
+	if p.trailingSlash(path) {
+		urlSegments = append(urlSegments, "")
+	}
 	request := &postman.Request{
-		URL: &postman.URL{
-			Raw:       "{{base_url}}" + processedPath,
-			Host:      []string{"{{base_url}}"},
-			Path:      pathSegments,
-			Query:     queryParams,
-			Variables: urlVariables,
-		},
+		URL:    p.requestURL(baseVariable, urlSegments, queryParams, urlVariables),
 		Method: postman.Method(method),
 		Header: []*postman.Header{},
 		Body:   &postman.Body{},
 	}
+	if fragment != "" {
+		request.URL.Hash = fragment
+		request.URL.Raw += "#" + fragment
+	}
 
 	if len(formParams) > 0 {
 		request.Body.Mode = "formdata"
 		request.Body.FormData = formParams
 		request.Header = append(request.Header, &postman.Header{Key: "Content-Type", Value: "multipart/form-data"})
 	} else if len(jsonParams) > 0 {
-		bodyBytes, err := json.MarshalIndent(jsonParams, "", "  ")
+		raw, err := p.marshalJSONBody(jsonParams, unquotedRefs, jsonFields)
 		if err != nil {
 			return fmt.Errorf("failed to marshal json body: %w", err)
 		}
 		request.Body.Mode = "raw"
-		request.Body.Raw = string(bodyBytes)
+		request.Body.Raw = raw
 		request.Body.Options = &postman.BodyOptions{
 			Raw: postman.BodyOptionsRaw{Language: "json"},
 		}
 		request.Header = append(request.Header, &postman.Header{Key: "Content-Type", Value: "application/json"})
 	}
 
-	item := postman.CreateItem(postman.Item{
-		Name:      pathSegments[len(pathSegments)-1],
-		Request:   request,
-		Responses: []*postman.Response{},
-	})
+	if language, ok := spec["bodyLanguage"].(string); ok && language != "json" {
+		if err := p.setBodyLanguage(request, spec, language, typ, request.Body.Raw); err != nil {
+			return err
+		}
+	}
 
-	folderSegments := pathSegments[:len(pathSegments)-1]
-	currentSlicePtr := &p.collection.Items
+	if request.Body.Mode == "" {
+		// An empty mode is rejected by the collection schema.
+		request.Body = nil
+	}
 
-	for _, segment := range folderSegments {
-		var foundFolder *postman.Items
+	if headers, ok := spec["headers"].(map[string]string); ok {
+		keys := make([]string, 0, len(headers))
+		for key := range headers {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			request.Header = append(request.Header, &postman.Header{Key: key, Value: headers[key]})
+		}
+	}
+
+	stream, _ := spec["sse"].(bool)
+	if stream {
+		request.Header = append(request.Header, &postman.Header{Key: "Accept", Value: "text/event-stream"})
+	}
+
+	events := []*postman.Event{}
 
-		for _, existingItem := range *currentSlicePtr {
-			if existingItem.Name == segment && existingItem.Request == nil {
-				foundFolder = existingItem
-				break
+	maxResponseTimeMs := p.maxResponseTimeMs
+	if ms, ok := specInt(spec, "maxResponseTimeMs"); ok {
+		maxResponseTimeMs = ms
+	}
+	// Event streams stay open, so their response time says nothing about the
+	// endpoint.
+	if maxResponseTimeMs > 0 && !stream {
+		events = appendScript(events, postman.Test, responseTimeScript(maxResponseTimeMs)...)
+	}
+
+	responses := []*postman.Response{}
+
+	declaredResponses := []routeResponse{}
+	if responseType, ok := spec["responseType"].(reflect.Type); ok {
+		status, ok := specInt(spec, "responseStatus")
+		if !ok {
+			status = http.StatusOK
+		}
+		if p.envelopes(spec, status) {
+			responseType = p.envelopedType(responseType)
+		}
+		declaredResponses = append(declaredResponses, routeResponse{status: status, typ: responseType})
+	}
+	for _, r := range specResponses(spec) {
+		if slices.ContainsFunc(declaredResponses, func(d routeResponse) bool { return d.status == r.status }) {
+			continue
+		}
+		if r.typ != nil && p.envelopes(spec, r.status) {
+			r.typ = p.envelopedType(r.typ)
+		}
+		declaredResponses = append(declaredResponses, r)
+	}
+	for i, r := range declaredResponses {
+		saved := []*postman.Response{}
+		if produces := specProduces(spec); len(produces) > 0 && r.typ != nil && !stream {
+			for _, contentType := range produces {
+				response, err := p.negotiatedResponse(request, r, contentType)
+				if err != nil {
+					return err
+				}
+				saved = append(saved, response)
+			}
+		} else {
+			response, err := p.savedResponse(request, r)
+			if err != nil {
+				return err
 			}
+			if stream {
+				eventStreamResponse(response)
+			}
+			saved = append(saved, response)
+		}
+
+		declaredResponses[i].headers = specResponseHeaders(spec, r.status)
+		for _, response := range saved {
+			addResponseHeaders(response, declaredResponses[i].headers)
+			addResponseCookies(response, specResponseCookies(spec, r.status))
+		}
+		responses = append(responses, saved...)
+	}
+
+	boundaries := p.boundaryExamples
+	if enabled, ok := spec["boundaryExamples"].(bool); ok {
+		boundaries = enabled
+	}
+	if boundaries && isJSONBody(request) {
+		examples, err := p.boundaryResponses(request, jsonParams, unquotedRefs, jsonFields)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, examples...)
+	}
+
+	name := p.routeName(spec, pathSegments)
+	description := p.routeDescription(spec)
+	if isProtoMessage(typ) {
+		description = strings.TrimSpace(description + "\n\n" + protoOneofDescription(typ))
+	}
+	if stream {
+		description = strings.TrimSpace(description + "\n\n" + sseDescription)
+	}
+	events = appendScript(events, postman.Test, specScript(spec, "testScript")...)
+	dependencies, err := p.dependencyScript(specDependencies(spec), baseVariable)
+	if err != nil {
+		return err
+	}
+	events = appendScript(events, postman.PreRequest, dependencies...)
+	events, err = p.routeScriptFiles(events, spec)
+	if err != nil {
+		return err
+	}
+	if parts := urlPartsDescription(matrixParams, fragment); parts != "" {
+		description = strings.TrimSpace(description + "\n\n" + parts)
+	}
+	if p.isSessionLogin(method, path) {
+		description = strings.TrimSpace(description + "\n\n" + p.sessionLoginDescription())
+		events = appendScript(events, postman.Test, sessionCookieScript(p.sessionAuth.CookieName)...)
+	}
+	if limit, ok := p.routeRateLimit(spec, pathSegments); ok {
+		description = strings.TrimSpace(description + "\n\n" + rateLimitDescription(limit))
+		if limit.AssertHeaders {
+			events = appendScript(events, postman.Test, rateLimitScript(limit)...)
 		}
+	}
+	items := []*postman.Items{postman.CreateItem(postman.Item{
+		Name:        name,
+		Description: description,
+		Request:     request,
+		Responses:   responses,
+		Events:      events,
+	})}
+
+	if p.wantsCompanions(spec, method) {
+		items = append(items, p.companionItems(name, request)...)
+	}
+
+	negativeTests := p.negativeTests
+	if enabled, ok := spec["negativeTests"].(bool); ok {
+		negativeTests = enabled
+	}
+	if negativeTests && isJSONBody(request) {
+		negatives, err := p.negativeItems(name, request, jsonParams, unquotedRefs, jsonFields)
+		if err != nil {
+			return err
+		}
+		items = append(items, negatives...)
+	}
+
+	if behavior := p.protocolProfileBehaviorFor(spec, stream); behavior != nil {
+		for _, item := range items {
+			item.ProtocolProfileBehavior = behavior
+		}
+	}
+
+	if err := p.addToFolder(p.routeFolder(spec, pathSegments), items...); err != nil {
+		return err
+	}
+
+	p.applyRouteChanges()
+
+	p.debugf("%s %s: registered as %q with %d query params, %d path variables and %d saved responses",
+		method, path, name, len(queryParams), len(urlVariables), len(responses))
+
+	p.routes = append(p.routes, &route{
+		method:    method,
+		path:      path,
+		name:      name,
+		inputType: inputType,
+		responses: declaredResponses,
+		request:   request,
+		item:      items[0],
+		items:     items,
+
+		description:     description,
+		missingExamples: missingExamples,
+		baseVariable:    baseVariable,
+		stream:          stream,
+	})
+
+	return nil
+}
+
+// addToFolder appends items to the folder at folderSegments, creating the
+// folders that do not exist yet. Folders and requests of the same name are
+// handled as selected by SetFolderCollision.
+func (p *PostmanGen) addToFolder(folderSegments []string, items ...*postman.Items) error {
+	if p.folderCollision == FolderCollisionError {
+		if err := p.checkFolderCollision(folderSegments, requestName(items)); err != nil {
+			return err
+		}
+	}
+
+	currentSlicePtr := &p.collection.Items
+
+	for _, segment := range folderSegments {
+		foundFolder := p.findFolder(*currentSlicePtr, segment)
 
 		if foundFolder == nil {
 			newFolder := &postman.Items{
 				Name:  segment,
 				Items: make([]*postman.Items, 0),
 			}
-			*currentSlicePtr = append(*currentSlicePtr, newFolder)
+			if i := p.findRequest(*currentSlicePtr, segment); i >= 0 {
+				p.debugf("folder %q collides with request %q", segment, (*currentSlicePtr)[i].Name)
+				if p.folderCollision == FolderCollisionNest {
+					moved := p.routeItemsIn(*currentSlicePtr, (*currentSlicePtr)[i])
+					newFolder.Items = append(newFolder.Items, moved...)
+					(*currentSlicePtr)[i] = newFolder
+					*currentSlicePtr = slices.DeleteFunc(*currentSlicePtr, func(item *postman.Items) bool {
+						return slices.Contains(moved, item)
+					})
+				}
+			}
+			if !slices.Contains(*currentSlicePtr, newFolder) {
+				*currentSlicePtr = p.appendItems(*currentSlicePtr, newFolder)
+			}
 			foundFolder = newFolder
 		} else {
 			if foundFolder.Items == nil {
@@ -309,27 +832,324 @@ func (p *PostmanGen) Register(spec map[string]any) error {
 		currentSlicePtr = &foundFolder.Items
 	}
 
-	*currentSlicePtr = append(*currentSlicePtr, item)
+	if len(items) > 0 && items[0].Request != nil {
+		if folder := p.findFolder(*currentSlicePtr, items[0].Name); folder != nil {
+			p.debugf("request %q collides with folder %q", items[0].Name, folder.Name)
+			if p.folderCollision == FolderCollisionNest {
+				currentSlicePtr = &folder.Items
+			}
+		}
+	}
+
+	*currentSlicePtr = p.appendItems(*currentSlicePtr, items...)
+	return nil
+}
+
+// savedResponse builds the saved example of r for request.
+// Responses without a type have no body.
+func (p *PostmanGen) savedResponse(request *postman.Request, r routeResponse) (*postman.Response, error) {
+	response := &postman.Response{
+		Name:            statusLabel(r.status),
+		OriginalRequest: request,
+		Status:          http.StatusText(r.status),
+		Code:            r.status,
+		Headers:         &postman.HeaderList{Headers: []*postman.Header{}},
+	}
+	if r.typ == nil {
+		return response, nil
+	}
+
+	body, err := p.exampleJSON(r.typ)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response body: %w", err)
+	}
+	response.Headers.Headers = append(response.Headers.Headers, &postman.Header{Key: "Content-Type", Value: "application/json"})
+	response.Body = body
+	response.PreviewLanguage = "json"
+	return response, nil
+}
+
+// statusLabel returns the status code followed by its text, e.g. "404 Not Found".
+func statusLabel(status int) string {
+	return fmt.Sprintf("%d %s", status, http.StatusText(status))
+}
+
+// marshalJSONBody renders params as a JSON body, with the members in the
+// order of fields if SetPreserveFieldOrder is enabled. The unquotedRefs
+// {{variable}} references are unquoted so Postman substitutes non-string
+// values.
+func (p *PostmanGen) marshalJSONBody(params map[string]any, unquotedRefs []string, fields []jsonBodyField) (string, error) {
+	order := make([]exampleField, len(fields))
+	for i, f := range fields {
+		order[i] = exampleField{name: f.key, typ: f.field.Type}
+	}
+	bodyBytes, err := p.encodeJSONObject(params, nil, order)
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range unquotedRefs {
+		bodyBytes = bytes.ReplaceAll(bodyBytes, []byte(`"`+ref+`"`), []byte(ref))
+	}
+	return string(bodyBytes), nil
+}
+
+// specResponses returns the responses of the "responses" spec key, a
+// map[int]reflect.Type or map[int]any of types or values keyed by status, in
+// status order.
+func specResponses(spec map[string]any) []routeResponse {
+	responses := []routeResponse{}
+	switch v := spec["responses"].(type) {
+	case map[int]reflect.Type:
+		for _, status := range slices.Sorted(maps.Keys(v)) {
+			responses = append(responses, routeResponse{status: status, typ: v[status]})
+		}
+	case map[int]any:
+		for _, status := range slices.Sorted(maps.Keys(v)) {
+			var typ reflect.Type
+			if v[status] != nil {
+				typ = typeOf(v[status])
+			}
+			responses = append(responses, routeResponse{status: status, typ: typ})
+		}
+	}
+	return responses
+}
+
+// specScript returns the script lines of a spec key holding a string or a
+// []string.
+func specScript(spec map[string]any, key string) []string {
+	switch v := spec[key].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// staticQueryParams returns the parameters of the "query" spec key, a
+// map[string]string of fixed query parameters such as api-version, in key
+// order. Keys the input already declares are left out.
+func staticQueryParams(spec map[string]any, declared []*postman.QueryParam) []*postman.QueryParam {
+	query, _ := spec["query"].(map[string]string)
+	params := []*postman.QueryParam{}
+	for _, key := range slices.Sorted(maps.Keys(query)) {
+		if slices.ContainsFunc(declared, func(q *postman.QueryParam) bool { return q.Key == key }) {
+			continue
+		}
+		params = append(params, &postman.QueryParam{Key: key, Value: query[key]})
+	}
+	return params
+}
 
+// specExamples returns the "examples" spec key, example values keyed by field
+// name that override the examples of the route's fields.
+func specExamples(spec map[string]any) map[string]any {
+	switch v := spec["examples"].(type) {
+	case map[string]any:
+		return v
+	case map[string]string:
+		examples := make(map[string]any, len(v))
+		for key, value := range v {
+			examples[key] = value
+		}
+		return examples
+	}
 	return nil
 }
 
-func (p *PostmanGen) WriteToFile(filename string) error {
+// routeExample returns the example the route being registered sets for the
+// first of keys with one.
+func (p *PostmanGen) routeExample(keys ...string) (any, bool) {
+	for _, key := range keys {
+		if value, ok := p.routeExamples[key]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// rootName is the default name of the route of the root path.
+const rootName = "Root"
+
+// SetRootName sets the name of the request of the root path "/", "Root" by
+// default, e.g. "Index". A "name" spec key overrides it.
+func (p *PostmanGen) SetRootName(name string) *PostmanGen {
+	p.rootName = name
+	return p
+}
+
+// routeName returns the request name of a route, its "name" spec key or the
+// last segment of its path. The root path is named by SetRootName.
+func (p *PostmanGen) routeName(spec map[string]any, pathSegments []string) string {
+	if name, ok := spec["name"].(string); ok && name != "" {
+		return name
+	}
+	if isRootPath(pathSegments) {
+		return defaultString(p.rootName, p.translate(rootName))
+	}
+	return pathSegments[len(pathSegments)-1]
+}
+
+// isRootPath reports whether the segments of a path are those of "/".
+func isRootPath(pathSegments []string) bool {
+	return len(pathSegments) == 1 && pathSegments[0] == ""
+}
+
+// routeFolder returns the folder names of a route, its "folder" spec key or
+// the folders of its path selected by SetFolderStrategy.
+func (p *PostmanGen) routeFolder(spec map[string]any, pathSegments []string) []string {
+	if folder, ok := spec["folder"].(string); ok {
+		return specFolder(folder)
+	}
+	if p.folderStrategy == FolderByResource {
+		return resourceFolder(pathSegments)
+	}
+	return pathSegments[:len(pathSegments)-1]
+}
+
+// specFolder splits the "folder" spec key, e.g. "Admin/Operations", into
+// folder names. An empty folder places items at the top level.
+func specFolder(folder string) []string {
+	folder = strings.Trim(folder, "/")
+	if folder == "" {
+		return nil
+	}
+	return strings.Split(folder, "/")
+}
+
+func specInt(spec map[string]any, key string) (int, bool) {
+	switch v := spec[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+func (p *PostmanGen) WriteToFile(filename string, opts ...WriteOption) error {
+	data, state, err := p.renderForWrite(opts...)
+	if err != nil {
+		return err
+	}
+
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	err = p.collection.Write(file, postman.V210)
+	_, err = file.Write(data)
 	if err != nil {
 		return err
 	}
-	return nil
+	return p.saveSyncState(state)
+}
+
+func (p *PostmanGen) Write(w io.Writer, opts ...WriteOption) error {
+	data, state, err := p.renderForWrite(opts...)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return p.saveSyncState(state)
+}
+
+// Bytes returns the collection document written by Write, e.g. to serve it
+// from an HTTP handler or embed it in another document.
+func (p *PostmanGen) Bytes(opts ...WriteOption) ([]byte, error) {
+	return p.render(opts...)
+}
+
+// MarshalJSON implements json.Marshaler, so a PostmanGen can be embedded in
+// other JSON documents as its collection.
+func (p *PostmanGen) MarshalJSON() ([]byte, error) {
+	return p.render()
+}
+
+// render returns the collection document written by Write and WriteToFile.
+// It has no side effects: the ids SetSyncState assigns are only saved by
+// the functions writing the collection, through renderForWrite.
+func (p *PostmanGen) render(opts ...WriteOption) ([]byte, error) {
+	data, _, err := p.renderForWrite(opts...)
+	return data, err
+}
+
+// renderForWrite is render, also returning the sync state to save with
+// saveSyncState once the document is written.
+func (p *PostmanGen) renderForWrite(opts ...WriteOption) ([]byte, *syncState, error) {
+	p.renderMu.Lock()
+	defer p.renderMu.Unlock()
+	return p.renderDocument(p.collection.Items, opts...)
 }
 
-func (p *PostmanGen) Write(w io.Writer) error {
-	return p.collection.Write(w, postman.V210)
+// renderItems is render with items in place of the collection's items. The
+// caller must hold renderMu. The folder added by SetGettingStarted is
+// rendered before items.
+func (p *PostmanGen) renderItems(items []*postman.Items, opts ...WriteOption) ([]byte, error) {
+	data, _, err := p.renderDocument(items, opts...)
+	return data, err
+}
+
+// renderDocument is renderItems, also returning the sync state to save once
+// the document is written.
+func (p *PostmanGen) renderDocument(items []*postman.Items, opts ...WriteOption) ([]byte, *syncState, error) {
+	collectionItems := p.collection.Items
+	defer func() { p.collection.Items = collectionItems }()
+
+	settings := writeSettings{}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	restoreShared := p.addSharedVariables(settings.sharedVariables)
+	defer restoreShared()
+	restoreSecrets := p.maskSecrets(settings)
+	defer restoreSecrets()
+	items = p.withGettingStarted(items)
+	p.collection.Items = items
+
+	restoreScripts, err := p.addScriptFiles()
+	defer restoreScripts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	descriptions, restore := p.markDisabledQuery()
+	description := p.collection.Info.Description
+	p.collection.Info.Description = markdownDescription(p.translate(description.Content) + p.certificateNotes() + p.changelogNotes())
+	buf := &bytes.Buffer{}
+	err = p.collection.Write(buf, postman.V210)
+	p.collection.Info.Description = description
+	restore()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := patchDisabledQuery(buf.Bytes(), descriptions)
+	if err == nil && settings.contentHashes {
+		data, err = p.addContentHashes(data, items)
+	}
+	if err != nil || p.syncStateFile == "" {
+		return data, nil, err
+	}
+	return p.addSyncIDs(data, items)
+}
+
+// markdownDescription returns a collection description with content. The
+// library writes descriptions without a type as unescaped strings, producing
+// invalid JSON for content with quotes or newlines, so such content is given
+// the text/markdown type, which is encoded as an object.
+func markdownDescription(content string) postman.Description {
+	quoted, _ := json.Marshal(content)
+	if string(quoted) == `"`+content+`"` {
+		return postman.Description{Content: content}
+	}
+	return postman.Description{Content: content, Type: "text/markdown"}
 }
 
 func (p *PostmanGen) TypeZeroValue(t reflect.Type, preferString bool) any {
@@ -347,6 +1167,9 @@ func (p *PostmanGen) TypeZeroValue(t reflect.Type, preferString bool) any {
 	if t.Kind() == reflect.Slice {
 		return []any{p.TypeZeroValue(t.Elem(), preferString)}
 	}
+	if p.int64AsString && (t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64) {
+		return "0"
+	}
 
 	zero := reflect.Zero(t)
 	return zero.Interface()
@@ -0,0 +1,54 @@
+package postmangen
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type benchAddress struct {
+	Street string `json:"street" example:"Main St"`
+	City   string `json:"city" example:"Springfield"`
+}
+
+type benchRequest struct {
+	ID      string       `param:"id" example:"7"`
+	Email   string       `json:"email" example:"jane@example.com"`
+	Name    string       `json:"name" example:"Jane"`
+	Age     int          `json:"age" example:"30"`
+	Address benchAddress `json:"address"`
+	Tags    []string     `json:"tags"`
+	Page    int          `query:"page"`
+	Cursor  string       `query:"cursor" disabled:"true" description:"Cursor of the next page"`
+}
+
+type benchResponse struct {
+	ID        string         `json:"id"`
+	Items     []benchAddress `json:"items"`
+	Total     int            `json:"total"`
+	CreatedAt string         `json:"created_at"`
+}
+
+// BenchmarkRegister registers 1000 routes with request and response bodies
+// and renders the collection. Run it with
+// go test -run '^$' -bench Register -benchmem.
+func BenchmarkRegister(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := NewPostmanGen("Bench", "")
+		for j := 0; j < 1000; j++ {
+			err := p.Register(RouteSpec{
+				"method":       "POST",
+				"path":         fmt.Sprintf("/group%d/resource%d/:id", j%10, j),
+				"inputType":    reflect.TypeOf(benchRequest{}),
+				"responseType": reflect.TypeOf(benchResponse{}),
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		if _, err := p.Bytes(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package postmangentest provides helpers for testing postmangen generator
+// setups with golden files.
+package postmangentest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Lexographics/go-postmangen"
+)
+
+var update = flag.Bool("postmangen.update", false, "rewrite postmangen golden files with the generated collections")
+
+// AssertCollection compares the collection generated by pg with the golden
+// file and fails the test when they differ. Both are normalized with
+// postmangen.NormalizeCollection first, so Postman ids and object key order do
+// not matter.
+//
+// Running the tests with -postmangen.update, or with POSTMANGEN_UPDATE=1 in the
+// environment, writes the normalized collection to the golden file instead.
+func AssertCollection(t testing.TB, pg *postmangen.PostmanGen, goldenFile string) {
+	t.Helper()
+
+	// Bytes, unlike Write, does not save the SetSyncState file.
+	data, err := pg.Bytes()
+	if err != nil {
+		t.Fatalf("postmangentest: generating collection: %v", err)
+	}
+	got, err := postmangen.NormalizeCollection(data)
+	if err != nil {
+		t.Fatalf("postmangentest: normalizing generated collection: %v", err)
+	}
+
+	if *update || os.Getenv("POSTMANGEN_UPDATE") == "1" {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0o755); err != nil {
+			t.Fatalf("postmangentest: %v", err)
+		}
+		if err := os.WriteFile(goldenFile, append(got, '\n'), 0o644); err != nil {
+			t.Fatalf("postmangentest: %v", err)
+		}
+		return
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("postmangentest: %v (run with -postmangen.update to create it)", err)
+	}
+	want, err := postmangen.NormalizeCollection(golden)
+	if err != nil {
+		t.Fatalf("postmangentest: normalizing %s: %v", goldenFile, err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("postmangentest: collection differs from %s (run with -postmangen.update to accept):\n%s", goldenFile, lineDiff(string(want), string(got)))
+	}
+}
+
+// lineDiff shows the lines around the first difference between want and got.
+func lineDiff(want string, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	first := 0
+	for first < len(wantLines) && first < len(gotLines) && wantLines[first] == gotLines[first] {
+		first++
+	}
+
+	const context = 3
+	start := max(first-context, 0)
+
+	b := &strings.Builder{}
+	for i := start; i < first; i++ {
+		fmt.Fprintf(b, "  %s\n", wantLines[i])
+	}
+	for i := first; i < min(first+context, len(wantLines)); i++ {
+		fmt.Fprintf(b, "- %s\n", wantLines[i])
+	}
+	for i := first; i < min(first+context, len(gotLines)); i++ {
+		fmt.Fprintf(b, "+ %s\n", gotLines[i])
+	}
+	return b.String()
+}
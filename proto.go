@@ -0,0 +1,287 @@
+package postmangen
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// protoExample returns an example of how a generated message type t is
+// encoded by protojson: fields use their JSON names, 64-bit integers are
+// strings and enums are their value names. Oneofs are represented by their
+// first variant. The fields are read from the message descriptor.
+func (p *PostmanGen) protoExample(t reflect.Type) any {
+	message, ok := reflect.New(derefType(t)).Interface().(protoreflect.ProtoMessage)
+	if !ok {
+		return nil
+	}
+	return p.protoMessageExample(message.ProtoReflect().Descriptor(), map[protoreflect.FullName]bool{})
+}
+
+// protoMessageExample returns the example of the message described by md.
+func (p *PostmanGen) protoMessageExample(md protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]bool) any {
+	if value, ok := wellKnownExample(md.FullName()); ok {
+		return value
+	}
+	if visiting[md.FullName()] {
+		return nil
+	}
+	visiting[md.FullName()] = true
+	defer delete(visiting, md.FullName())
+
+	obj := map[string]any{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			continue
+		}
+		obj[field.JSONName()] = p.protoFieldExample(field, visiting)
+	}
+	for _, oneof := range protoOneofs(md) {
+		variant := oneof.variants[0]
+		if defaultValue, ok := p.placeholderDefaults[variant.name]; ok {
+			p.usedPlaceholders[variant.name] = true
+			obj[variant.name] = defaultValue
+			continue
+		}
+		obj[variant.name] = variant.example
+	}
+	return obj
+}
+
+// protoInputFields maps the fields of the generated message t to the parts of
+// a request the way gRPC HTTP transcoding does: fields named by a path
+// parameter fill it, and the other fields are query parameters for methods
+// without a body (GET, HEAD, DELETE) and JSON body members otherwise.
+func (p *PostmanGen) protoInputFields(t reflect.Type, method string, path string) (body map[string]any, query []*postman.QueryParam, variables []*postman.Variable) {
+	body = map[string]any{}
+	query = []*postman.QueryParam{}
+	variables = []*postman.Variable{}
+
+	pathKeys := []string{}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if key, _, ok := pathParamKey(segment); ok {
+			pathKeys = append(pathKeys, key)
+		}
+	}
+
+	// Path parameters may use either the proto or the JSON name of a field.
+	protoNames := map[string]string{}
+	if message, ok := reflect.New(derefType(t)).Interface().(protoreflect.ProtoMessage); ok {
+		fields := message.ProtoReflect().Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			protoNames[fields.Get(i).JSONName()] = string(fields.Get(i).Name())
+		}
+	}
+
+	example, _ := p.protoExample(t).(map[string]any)
+	names := make([]string, 0, len(example))
+	for name := range example {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	upper := strings.ToUpper(method)
+	withoutBody := upper == http.MethodGet || upper == http.MethodHead || upper == http.MethodDelete
+	for _, name := range names {
+		value := example[name]
+		switch {
+		case slices.Contains(pathKeys, name):
+			variables = append(variables, &postman.Variable{Key: name, Value: fmt.Sprint(value), Type: "string"})
+		case protoNames[name] != "" && slices.Contains(pathKeys, protoNames[name]):
+			variables = append(variables, &postman.Variable{Key: protoNames[name], Value: fmt.Sprint(value), Type: "string"})
+		case withoutBody:
+			switch value.(type) {
+			case map[string]any:
+				// Nested messages have no query form in an example.
+			case []any:
+				query = append(query, p.queryArrayParams(name, value, "")...)
+			default:
+				description := ""
+				query = append(query, &postman.QueryParam{Key: name, Value: fmt.Sprint(value), Description: &description})
+			}
+		default:
+			body[name] = value
+		}
+	}
+	return body, query, variables
+}
+
+// protoFieldExample returns the example of field, using a default
+// placeholder named after its JSON name if there is one.
+func (p *PostmanGen) protoFieldExample(field protoreflect.FieldDescriptor, visiting map[protoreflect.FullName]bool) any {
+	if defaultValue, ok := p.placeholderDefaults[field.JSONName()]; ok {
+		p.usedPlaceholders[field.JSONName()] = true
+		return coerceExample(defaultValue, protoPlaceholderType(field))
+	}
+
+	value := func(field protoreflect.FieldDescriptor) any {
+		if kind := field.Kind(); kind == protoreflect.MessageKind || kind == protoreflect.GroupKind {
+			return p.protoMessageExample(field.Message(), visiting)
+		}
+		return protoKindExample(field)
+	}
+	switch {
+	case field.IsMap():
+		return map[string]any{"key": value(field.MapValue())}
+	case field.IsList():
+		return []any{value(field)}
+	}
+	return value(field)
+}
+
+// protoPlaceholderType returns the Go type coerceExample converts placeholder
+// defaults of field to. 64-bit integers stay strings, as protojson encodes
+// them.
+func protoPlaceholderType(field protoreflect.FieldDescriptor) reflect.Type {
+	if field.IsList() || field.IsMap() {
+		return reflect.TypeOf([]any{})
+	}
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return reflect.TypeOf(false)
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return reflect.TypeOf(float64(0))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return reflect.TypeOf(int32(0))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return reflect.TypeOf(map[string]any{})
+	}
+	return reflect.TypeOf("")
+}
+
+// isProtoMessage reports whether t is a message generated by protoc-gen-go.
+func isProtoMessage(t reflect.Type) bool {
+	return reflect.PointerTo(derefType(t)).Implements(protoMessageType)
+}
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// protoOneof is a oneof of a generated message.
+type protoOneof struct {
+	name     string
+	variants []protoVariant
+}
+
+// protoVariant is a field of a oneof, with its protojson name.
+type protoVariant struct {
+	name    string
+	example any
+}
+
+// protoOneofs returns the oneofs of the message described by md. The
+// variants are not visible in the Go struct, which only has an interface
+// field per oneof.
+func protoOneofs(md protoreflect.MessageDescriptor) []protoOneof {
+	result := []protoOneof{}
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneof := oneofs.Get(i)
+		if oneof.IsSynthetic() {
+			// proto3 optional fields are wrapped in synthetic oneofs but are
+			// plain pointer fields in Go.
+			continue
+		}
+
+		fields := oneof.Fields()
+		variants := []protoVariant{}
+		for j := 0; j < fields.Len(); j++ {
+			field := fields.Get(j)
+			variants = append(variants, protoVariant{
+				name:    field.JSONName(),
+				example: protoKindExample(field),
+			})
+		}
+		if len(variants) > 0 {
+			result = append(result, protoOneof{name: string(oneof.Name()), variants: variants})
+		}
+	}
+	return result
+}
+
+// protoKindExample returns the example of a field known only by its
+// descriptor, based on its kind.
+func protoKindExample(field protoreflect.FieldDescriptor) any {
+	switch field.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return ""
+	case protoreflect.BoolKind:
+		return false
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "0"
+	case protoreflect.EnumKind:
+		if values := field.Enum().Values(); values.Len() > 0 {
+			return string(values.Get(0).Name())
+		}
+		return ""
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if value, ok := wellKnownExample(field.Message().FullName()); ok {
+			return value
+		}
+		return map[string]any{}
+	}
+	return 0
+}
+
+// protoOneofDescription documents the variants of the oneofs of t, which the
+// example body can only show one of.
+func protoOneofDescription(t reflect.Type) string {
+	message, ok := reflect.New(derefType(t)).Interface().(protoreflect.ProtoMessage)
+	if !ok {
+		return ""
+	}
+
+	lines := []string{}
+	for _, oneof := range protoOneofs(message.ProtoReflect().Descriptor()) {
+		names := []string{}
+		for _, variant := range oneof.variants {
+			names = append(names, "`"+variant.name+"`")
+		}
+		lines = append(lines, fmt.Sprintf("- `%s`: set exactly one of %s", oneof.name, strings.Join(names, ", ")))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "Oneof fields:\n" + strings.Join(lines, "\n")
+}
+
+// wellKnownExample returns the JSON form of the well-known types protojson
+// encodes specially.
+func wellKnownExample(name protoreflect.FullName) (any, bool) {
+	if name.Parent() != "google.protobuf" {
+		return nil, false
+	}
+
+	switch name.Name() {
+	case "Timestamp":
+		return "1970-01-01T00:00:00Z", true
+	case "Duration":
+		return "0s", true
+	case "Struct":
+		return map[string]any{}, true
+	case "Value", "Any":
+		return nil, true
+	case "ListValue":
+		return []any{}, true
+	case "FieldMask":
+		return "", true
+	case "Empty":
+		return map[string]any{}, true
+	case "Int64Value", "UInt64Value":
+		return "0", true
+	case "StringValue", "BytesValue":
+		return "", true
+	case "BoolValue":
+		return false, true
+	case "DoubleValue", "FloatValue", "Int32Value", "UInt32Value":
+		return 0, true
+	}
+	return nil, false
+}
@@ -0,0 +1,58 @@
+package postmangen
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// RecordProxy returns a reverse proxy forwarding requests to the server at
+// target, e.g. a dev server at "http://localhost:8080". Like a Recorder, it
+// saves the exchanges matching registered routes as examples, which documents
+// endpoints by using them, e.g. through a client pointed at the proxy:
+//
+//	proxy, err := pg.RecordProxy("http://localhost:8080")
+//	go http.ListenAndServe(":9090", proxy)
+func (p *PostmanGen) RecordProxy(target string) (*Recorder, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy target: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy target %q: must be an absolute URL", target)
+	}
+	return p.Recorder(httputil.NewSingleHostReverseProxy(u)), nil
+}
+
+// sanitizedHeaderNames are the headers carrying credentials, which are
+// redacted from recorded exchanges.
+var sanitizedHeaderNames = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactedValue replaces the values of sensitive recorded headers and query
+// parameters.
+const redactedValue = "<redacted>"
+
+// sanitizeHeader returns a copy of header with the values of credential
+// headers, and of headers whose names look sensitive like variable names do,
+// redacted.
+func (p *PostmanGen) sanitizeHeader(header http.Header) http.Header {
+	sanitized := header.Clone()
+	for key, values := range sanitized {
+		if !slices.Contains(sanitizedHeaderNames, http.CanonicalHeaderKey(key)) && !p.isSensitiveName(key) {
+			continue
+		}
+		for i := range values {
+			values[i] = redactedValue
+		}
+	}
+	return sanitized
+}
+
+// isSensitiveName reports whether a recorded header or query parameter name
+// looks sensitive like variable names do, e.g. X-Api-Key or access_token.
+func (p *PostmanGen) isSensitiveName(name string) bool {
+	return p.isSensitive(strings.ReplaceAll(name, "-", "_"))
+}
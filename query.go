@@ -0,0 +1,174 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// QueryArrayStyle selects how slice fields with a query tag are serialized.
+type QueryArrayStyle int
+
+const (
+	// QueryArrayRepeat repeats the parameter for each value: tag=a&tag=b.
+	QueryArrayRepeat QueryArrayStyle = iota
+	// QueryArrayComma joins the values with commas: tag=a,b.
+	QueryArrayComma
+	// QueryArrayBrackets repeats the parameter with a [] suffix: tag[]=a&tag[]=b.
+	QueryArrayBrackets
+)
+
+// SetQueryArrayStyle selects how slice and array query fields are written.
+// The default is QueryArrayRepeat.
+func (p *PostmanGen) SetQueryArrayStyle(style QueryArrayStyle) *PostmanGen {
+	p.queryArrayStyle = style
+	return p
+}
+
+// isQueryArray reports whether a query field holds several values. Byte
+// slices are treated as a single value.
+func isQueryArray(t reflect.Type) bool {
+	t = derefType(t)
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
+
+// queryArrayValues splits the example of a slice query field into its values.
+// Examples may be written as a JSON array or as comma-separated values.
+func queryArrayValues(example any) []string {
+	values := []string{}
+	switch v := example.(type) {
+	case []any:
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+	case string:
+		var items []any
+		if err := json.Unmarshal([]byte(v), &items); err == nil {
+			return queryArrayValues(items)
+		}
+		values = strings.Split(v, ",")
+	default:
+		values = append(values, fmt.Sprint(v))
+	}
+	return values
+}
+
+// queryArrayParams returns the query entries of a slice field in the
+// configured style. Only the comma style, which has a single value, is
+// replaced by a data reference in data-driven runs.
+func (p *PostmanGen) queryArrayParams(key string, example any, description string) []*postman.QueryParam {
+	values := queryArrayValues(example)
+
+	if p.queryArrayStyle == QueryArrayComma {
+		value := strings.Join(values, ",")
+		p.recordDataValue(key, value)
+		if p.dataDriven {
+			value = dataReference(key)
+		}
+		return []*postman.QueryParam{{Key: key, Value: value, Description: &description}}
+	}
+
+	if p.queryArrayStyle == QueryArrayBrackets {
+		key += "[]"
+	}
+	params := []*postman.QueryParam{}
+	for _, value := range values {
+		params = append(params, &postman.QueryParam{Key: key, Value: value, Description: &description})
+	}
+	return params
+}
+
+// SetDisableOptionalQuery emits query parameters without a validate:"required"
+// rule as disabled, so they are listed unchecked in Postman's params table
+// instead of being sent with every request. A disabled:"true" or
+// disabled:"false" tag on the field takes precedence.
+func (p *PostmanGen) SetDisableOptionalQuery(enabled bool) *PostmanGen {
+	p.disableOptionalQuery = enabled
+	return p
+}
+
+// queryDisabled reports whether the query parameters of field are emitted
+// disabled.
+func (p *PostmanGen) queryDisabled(field reflect.StructField) bool {
+	if disabled, err := strconv.ParseBool(field.Tag.Get("disabled")); err == nil {
+		return disabled
+	}
+	return p.disableOptionalQuery && !fieldValidateRules(field).Required
+}
+
+// enabledQuery returns the query parameters of u that Postman sends.
+func (p *PostmanGen) enabledQuery(u *postman.URL) []*postman.QueryParam {
+	params := []*postman.QueryParam{}
+	for _, q := range u.Query {
+		if !p.isDisabledQuery(q) {
+			params = append(params, q)
+		}
+	}
+	return params
+}
+
+// disabledMarkerPrefix starts the placeholder descriptions that stand in for
+// disabled query parameters while the collection is encoded.
+const disabledMarkerPrefix = "postmangen:disabled:"
+
+var disabledMarker = []byte(`"description": "` + disabledMarkerPrefix)
+
+// markDisabledQuery replaces the descriptions of disabled query parameters by
+// numbered markers, since the library's QueryParam has no disabled field. It
+// returns the replaced descriptions, indexed by marker number, and a function
+// restoring them.
+func (p *PostmanGen) markDisabledQuery() (descriptions []*string, restore func()) {
+	originals := map[*postman.QueryParam]*string{}
+	for q := range p.disabledQuery {
+		originals[q] = q.Description
+		marker := disabledMarkerPrefix + strconv.Itoa(len(descriptions))
+		descriptions = append(descriptions, q.Description)
+		q.Description = &marker
+	}
+	return descriptions, func() {
+		for q, description := range originals {
+			q.Description = description
+		}
+	}
+}
+
+// patchDisabledQuery puts the descriptions replaced by markDisabledQuery back
+// and marks their parameters disabled. The output is scanned for markers
+// rather than matched with a regular expression, as it holds the whole
+// collection.
+func patchDisabledQuery(data []byte, descriptions []*string) ([]byte, error) {
+	if len(descriptions) == 0 {
+		return data, nil
+	}
+	patched := make([]byte, 0, len(data)+len(descriptions)*len(`,"disabled": true`))
+	for {
+		i := bytes.Index(data, disabledMarker)
+		if i < 0 {
+			break
+		}
+		start := bytes.LastIndexByte(data[:i], '\n') + 1
+		indent := data[start:i]
+		rest := data[i+len(disabledMarker):]
+		end := bytes.IndexByte(rest, '"')
+		n, err := strconv.Atoi(string(rest[:max(end, 0)]))
+		if end < 0 || err != nil || n >= len(descriptions) || len(bytes.Trim(indent, " \t")) > 0 ||
+			(end+1 < len(rest) && rest[end+1] != '\n') {
+			patched = append(patched, data[:i+len(disabledMarker)]...)
+			data = rest
+			continue
+		}
+		description, err := json.Marshal(descriptions[n])
+		if err != nil {
+			return nil, err
+		}
+		patched = append(patched, data[:i]...)
+		patched = fmt.Appendf(patched, "\"description\": %s,\n%s\"disabled\": true", description, indent)
+		data = rest[end+1:]
+	}
+	return append(patched, data...), nil
+}
@@ -0,0 +1,77 @@
+package postmangen
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RateLimit is the number of requests clients may send to a route per window.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+	// AssertHeaders adds a test checking that responses report the limit in
+	// the X-RateLimit-Limit header and carry X-RateLimit-Remaining.
+	AssertHeaders bool
+}
+
+// SetRateLimit documents the rate limit of the routes under pathPrefix (e.g.
+// "/search", or "" for every route) in their descriptions. The longest
+// matching prefix wins, and a route's "rateLimit" spec key takes precedence;
+// a zero RateLimit documents no limit.
+func (p *PostmanGen) SetRateLimit(pathPrefix string, limit RateLimit) *PostmanGen {
+	p.folderRateLimits[strings.Trim(pathPrefix, "/")] = limit
+	return p
+}
+
+// routeRateLimit returns the rate limit of a route, if it has one.
+func (p *PostmanGen) routeRateLimit(spec map[string]any, pathSegments []string) (RateLimit, bool) {
+	limit, ok := spec["rateLimit"].(RateLimit)
+	if !ok {
+		limit, ok = folderValue(p.folderRateLimits, pathSegments)
+	}
+	return limit, ok && limit.Requests > 0
+}
+
+// String describes the limit, e.g. "100 requests per minute".
+func (l RateLimit) String() string {
+	requests := fmt.Sprintf("%d requests", l.Requests)
+	if l.Requests == 1 {
+		requests = "1 request"
+	}
+
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+	for _, unit := range units {
+		if l.Window < unit.duration || l.Window%unit.duration != 0 {
+			continue
+		}
+		if n := l.Window / unit.duration; n > 1 {
+			return fmt.Sprintf("%s per %d %ss", requests, n, unit.name)
+		}
+		return requests + " per " + unit.name
+	}
+	return fmt.Sprintf("%s per %s", requests, l.Window)
+}
+
+// rateLimitDescription documents limit in a route description.
+func rateLimitDescription(limit RateLimit) string {
+	return "**Rate limit:** " + limit.String() + ". Exceeding it returns 429 Too Many Requests."
+}
+
+func rateLimitScript(limit RateLimit) []string {
+	return []string{
+		`pm.test("Rate limit headers are returned", function () {`,
+		`    pm.response.to.have.header("X-RateLimit-Limit");`,
+		fmt.Sprintf(`    pm.expect(Number(pm.response.headers.get("X-RateLimit-Limit"))).to.eql(%d);`, limit.Requests),
+		`    pm.response.to.have.header("X-RateLimit-Remaining");`,
+		`});`,
+	}
+}
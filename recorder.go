@@ -0,0 +1,190 @@
+package postmangen
+
+import (
+	"bytes"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// Recorder serves requests with an http.Handler and saves each exchange as an
+// example response of the registered route it matches, so the traffic of Go
+// tests documents the real behavior of the API. Requests matching no route are
+// served without being recorded. Credential headers, such as Authorization
+// and Cookie, and query parameters named like secrets, such as access_token,
+// are recorded redacted, and bodies are passed through the sanitizers added
+// with AddSanitizer. It is created by PostmanGen.Recorder and is safe for
+// concurrent use.
+type Recorder struct {
+	p       *PostmanGen
+	handler http.Handler
+}
+
+// Recorder returns a Recorder serving requests with handler, e.g.
+//
+//	rec := pg.Recorder(router)
+//	resp := rec.Do(httptest.NewRequest("GET", "/users/1", nil))
+//
+// Routes must be registered before their traffic is recorded.
+func (p *PostmanGen) Recorder(handler http.Handler) *Recorder {
+	return &Recorder{p: p, handler: handler}
+}
+
+// ServeHTTP serves r with the handler and records the exchange. The response
+// is buffered before it is written to w.
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := rec.serve(r)
+	maps.Copy(w.Header(), result.Header())
+	w.WriteHeader(result.Code)
+	w.Write(result.Body.Bytes())
+}
+
+// Do serves r with the handler through an httptest.ResponseRecorder, records
+// the exchange and returns the response.
+func (rec *Recorder) Do(r *http.Request) *http.Response {
+	return rec.serve(r).Result()
+}
+
+func (rec *Recorder) serve(r *http.Request) *httptest.ResponseRecorder {
+	var requestBody []byte
+	if r.Body != nil {
+		requestBody, _ = io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	result := httptest.NewRecorder()
+	rec.handler.ServeHTTP(result, r)
+	rec.p.recordExchange(r, requestBody, result)
+	return result
+}
+
+// recordExchange adds the exchange as a saved response of the route matching
+// r.
+func (p *PostmanGen) recordExchange(r *http.Request, requestBody []byte, result *httptest.ResponseRecorder) {
+	p.renderMu.Lock()
+	defer p.renderMu.Unlock()
+
+	path := r.URL.Path
+	if basePath := strings.Trim(p.baseURL.BasePath, "/"); basePath != "" {
+		path = strings.TrimPrefix(path, "/"+basePath)
+	}
+	rt := p.matchRoute(r.Method, path)
+	if rt == nil || rt.item == nil {
+		p.debugf("%s %s: no registered route, not recorded", r.Method, r.URL.Path)
+		return
+	}
+
+	query := []*postman.QueryParam{}
+	values := r.URL.Query()
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		for _, value := range values[key] {
+			if p.isSensitiveName(key) {
+				value = redactedValue
+			}
+			query = append(query, &postman.QueryParam{Key: key, Value: value})
+		}
+	}
+
+	request := *rt.request
+	request.URL = p.requestURL(rt.baseVariable, strings.Split(strings.Trim(path, "/"), "/"), query, nil)
+	request.Header = recordedHeaders(p.sanitizeHeader(r.Header))
+	request.Body = nil
+	if len(requestBody) > 0 {
+		request.Body = &postman.Body{Mode: "raw", Raw: p.sanitizeBody(string(requestBody))}
+		if language := previewLanguage(r.Header.Get("Content-Type")); language != "text" {
+			request.Body.Options = &postman.BodyOptions{Raw: postman.BodyOptionsRaw{Language: language}}
+		}
+	}
+
+	rt.item.Responses = append(rt.item.Responses, &postman.Response{
+		Name:            "Recorded " + statusLabel(result.Code),
+		OriginalRequest: &request,
+		Status:          http.StatusText(result.Code),
+		Code:            result.Code,
+		Headers:         &postman.HeaderList{Headers: recordedHeaders(p.sanitizeHeader(result.Header()))},
+		Body:            p.sanitizeBody(result.Body.String()),
+		PreviewLanguage: previewLanguage(result.Header().Get("Content-Type")),
+	})
+	p.debugf("%s %s: recorded %s as an example of %s %s", r.Method, r.URL.Path, statusLabel(result.Code), rt.method, rt.path)
+}
+
+// matchRoute returns the registered route whose method and path match, or nil.
+// Literal segments take precedence over parameters, so /users/me is matched
+// by a /users/me route before /users/:id.
+func (p *PostmanGen) matchRoute(method string, path string) *route {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var best *route
+	bestLiterals := -1
+	for _, rt := range p.routes {
+		if !strings.EqualFold(rt.method, method) {
+			continue
+		}
+		if literals, ok := matchPath(strings.Split(strings.Trim(rt.path, "/"), "/"), segments); ok && literals > bestLiterals {
+			best, bestLiterals = rt, literals
+		}
+	}
+	return best
+}
+
+// matchPath reports whether the segments of a request path match the
+// segments of a route path, and how many literal segments matched.
+func matchPath(pattern []string, segments []string) (int, bool) {
+	literals := 0
+	for i, segment := range pattern {
+		if _, catchAll, ok := pathParamKey(segment); ok {
+			if catchAll {
+				return literals, true
+			}
+			if i >= len(segments) || segments[i] == "" {
+				return 0, false
+			}
+			if pattern := pathParamConstraint(segment); pattern != "" {
+				if matched, err := regexp.MatchString(anchoredPattern(pattern), segments[i]); err == nil && !matched {
+					return 0, false
+				}
+			}
+			continue
+		}
+		if i >= len(segments) || segments[i] != segment {
+			return 0, false
+		}
+		literals++
+	}
+	return literals, len(pattern) == len(segments)
+}
+
+// recordedHeaders returns the entries of header in key order.
+func recordedHeaders(header http.Header) []*postman.Header {
+	headers := []*postman.Header{}
+	for _, key := range slices.Sorted(maps.Keys(header)) {
+		for _, value := range header[key] {
+			headers = append(headers, &postman.Header{Key: key, Value: value})
+		}
+	}
+	return headers
+}
+
+// previewLanguage returns the Postman body language of a content type.
+func previewLanguage(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return "json"
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return "xml"
+	case mediaType == "text/html":
+		return "html"
+	case mediaType == "application/javascript" || mediaType == "text/javascript":
+		return "javascript"
+	}
+	return "text"
+}
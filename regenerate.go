@@ -0,0 +1,488 @@
+package postmangen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// regenerateKey is the member of the items written by Regenerate recording
+// what they were generated from.
+const regenerateKey = "x-postmangen"
+
+// generatedItem is a request item of the collection with the endpoint it was
+// generated for and the hash of its route's types.
+type generatedItem struct {
+	endpoint string
+	// hash is empty for items that are not generated from a route's types,
+	// such as gRPC methods, which are always rebuilt.
+	hash string
+	// affected is set when the route's types reference a changed type.
+	affected bool
+	// optional are the fields of the route clients may omit, see
+	// optionalFields.
+	optional []string
+}
+
+// Regenerate rebuilds the items of the routes whose types changed and merges
+// them into existing, a collection previously written by Regenerate, returning
+// the merged document. Unchanged items are copied from existing as they are,
+// including manual edits, so watch modes do not render the whole collection on
+// every change.
+//
+// Items written by Regenerate record their endpoint, a hash of their route's
+// input and response types and a hash of their content under "x-postmangen",
+// see WithContentHashes. The items of a route are rebuilt when the type hash
+// differs from the one in existing, or when its types reference one of
+// changedTypes, e.g. a type whose placeholders changed while its declaration
+// did not. Other spec changes, such as a new description, need the type to be
+// listed. Items of routes no longer registered are removed, and items without
+// "x-postmangen", such as requests added by hand, are kept. The first
+// Regenerate of a collection written by Write rebuilds every item.
+// Collection-level members, such as variables, are always regenerated.
+func (p *PostmanGen) Regenerate(changedTypes []reflect.Type, existing io.Reader, opts ...WriteOption) ([]byte, error) {
+	old, err := decodeOrdered(json.NewDecoder(existing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read existing collection: %w", err)
+	}
+	if old.kind != 'o' {
+		return nil, errors.New("failed to read existing collection: must be a JSON object")
+	}
+	previous := map[string]string{}
+	collectRegenerated(memberOf(old, "item"), previous)
+
+	p.renderMu.Lock()
+	defer p.renderMu.Unlock()
+
+	changed := map[reflect.Type]bool{}
+	for _, t := range changedTypes {
+		changed[t] = true
+	}
+	generated := p.generatedItems(changed)
+
+	stale := map[*postman.Items]bool{}
+	current := map[string]bool{}
+	for item, g := range generated {
+		key := regenerateItemKey(g.endpoint, item.Name)
+		current[key] = true
+		if hash, ok := previous[key]; !ok || g.hash == "" || hash != g.hash || g.affected {
+			stale[item] = true
+		}
+	}
+
+	tree := staleTree(p.collection.Items, stale)
+	data, err := p.renderItems(tree, opts...)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeOrdered(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	fresh := memberOf(doc, "item")
+	if fresh == nil {
+		fresh = &orderedNode{kind: 'a'}
+	}
+	// The folder added by SetGettingStarted is rendered first, and replaces
+	// the existing one.
+	var start *orderedNode
+	if p.gettingStarted && len(fresh.values) > 0 {
+		start, fresh.values = fresh.values[0], fresh.values[1:]
+	}
+	if err := markGenerated(tree, fresh, generated); err != nil {
+		return nil, err
+	}
+
+	rebuilt := map[string]bool{}
+	for item := range stale {
+		rebuilt[regenerateItemKey(generated[item].endpoint, item.Name)] = true
+	}
+	editedNames, err := editedItems(memberOf(old, "item"), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, edited := range editedNames {
+		if endpoint, name, _ := strings.Cut(edited, ": "); rebuilt[regenerateItemKey(endpoint, name)] {
+			p.debugf("%s: rebuilt, discarding its manual edits", edited)
+		}
+	}
+	items := mergeRegenerated(memberOf(old, "item"), fresh, current, rebuilt)
+	if start != nil {
+		name, _ := scalarString(memberOf(start, "name"))
+		items.values = slices.DeleteFunc(items.values, func(item *orderedNode) bool {
+			existing, _ := scalarString(memberOf(item, "name"))
+			return existing == name && memberOf(item, "request") == nil
+		})
+		items.values = append([]*orderedNode{start}, items.values...)
+	}
+	setMember(doc, "item", items)
+
+	p.debugf("regenerated %d of %d items", len(stale), len(generated))
+	return encodeCollection(doc)
+}
+
+// encodeCollection writes a collection document indented like the library
+// writes collections.
+func encodeCollection(doc *orderedNode) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := writeOrderedJSON(buf, doc); err != nil {
+		return nil, err
+	}
+	out := &bytes.Buffer{}
+	if err := json.Indent(out, buf.Bytes(), "", "    "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// generatedItems returns the request items of the collection, with what
+// Regenerate records about them.
+func (p *PostmanGen) generatedItems(changed map[reflect.Type]bool) map[*postman.Items]generatedItem {
+	routes := map[*postman.Items]*route{}
+	for _, rt := range p.routes {
+		for _, item := range rt.items {
+			routes[item] = rt
+		}
+	}
+
+	generated := map[*postman.Items]generatedItem{}
+	var walk func(items []*postman.Items)
+	walk = func(items []*postman.Items) {
+		for _, item := range items {
+			if item.IsGroup() {
+				walk(item.Items)
+				continue
+			}
+			rt, ok := routes[item]
+			if !ok {
+				endpoint := item.Name
+				if item.Request != nil && item.Request.URL != nil {
+					endpoint = string(item.Request.Method) + " " + item.Request.URL.Raw
+				}
+				generated[item] = generatedItem{endpoint: endpoint}
+				continue
+			}
+			generated[item] = generatedItem{
+				endpoint: rt.method + " " + rt.path,
+				hash:     rt.typeHash(),
+				affected: rt.references(changed),
+				optional: p.optionalFields(rt),
+			}
+		}
+	}
+	walk(p.collection.Items)
+	return generated
+}
+
+// typeHash returns a hash of the route's method, path and name and of the
+// declarations of its input and response types, including the types they
+// reference.
+func (rt *route) typeHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s %s\n", rt.method, rt.path, rt.name)
+	seen := map[reflect.Type]bool{}
+	writeTypeDeclaration(h, rt.inputType, seen)
+	for _, r := range rt.responses {
+		fmt.Fprintf(h, "%d ", r.status)
+		writeTypeDeclaration(h, r.typ, seen)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// writeTypeDeclaration writes a description of t to w, with the fields of
+// structs and the types they reference.
+func writeTypeDeclaration(w io.Writer, t reflect.Type, seen map[reflect.Type]bool) {
+	if t == nil {
+		io.WriteString(w, "nil\n")
+		return
+	}
+	fmt.Fprintf(w, "%s %s\n", t.PkgPath(), t)
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		writeTypeDeclaration(w, t.Elem(), seen)
+	case reflect.Map:
+		writeTypeDeclaration(w, t.Key(), seen)
+		writeTypeDeclaration(w, t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fmt.Fprintf(w, "%s %q ", f.Name, f.Tag)
+			writeTypeDeclaration(w, f.Type, seen)
+		}
+	}
+}
+
+// references reports whether the route's input or response types are or
+// reference one of types.
+func (rt *route) references(types map[reflect.Type]bool) bool {
+	if len(types) == 0 {
+		return false
+	}
+	seen := map[reflect.Type]bool{}
+	if referencesType(rt.inputType, types, seen) {
+		return true
+	}
+	for _, r := range rt.responses {
+		if referencesType(r.typ, types, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func referencesType(t reflect.Type, types map[reflect.Type]bool, seen map[reflect.Type]bool) bool {
+	if t == nil || seen[t] {
+		return false
+	}
+	if types[t] {
+		return true
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		return referencesType(t.Elem(), types, seen)
+	case reflect.Map:
+		return referencesType(t.Key(), types, seen) || referencesType(t.Elem(), types, seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if referencesType(t.Field(i).Type, types, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// staleTree returns the folders of items reduced to the stale items. Folders
+// are copied, so the collection is unchanged.
+func staleTree(items []*postman.Items, stale map[*postman.Items]bool) []*postman.Items {
+	tree := []*postman.Items{}
+	for _, item := range items {
+		if item.IsGroup() {
+			if children := staleTree(item.Items, stale); len(children) > 0 {
+				folder := *item
+				folder.Items = children
+				tree = append(tree, &folder)
+			}
+			continue
+		}
+		if stale[item] {
+			tree = append(tree, item)
+		}
+	}
+	return tree
+}
+
+// markGenerated adds the "x-postmangen" member, with the content hash of the
+// item, to the rendered nodes of the request items of tree.
+func markGenerated(tree []*postman.Items, nodes *orderedNode, generated map[*postman.Items]generatedItem) error {
+	for i, item := range tree {
+		if i >= len(nodes.values) {
+			return nil
+		}
+		node := nodes.values[i]
+		if item.IsGroup() {
+			if children := memberOf(node, "item"); children != nil {
+				if err := markGenerated(item.Items, children, generated); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		g := generated[item]
+		marker := &orderedNode{kind: 'o'}
+		setMember(marker, "endpoint", &orderedNode{kind: 'v', scalar: g.endpoint})
+		if g.hash != "" {
+			setMember(marker, "typeHash", &orderedNode{kind: 'v', scalar: g.hash})
+		}
+		hash, err := contentHash(node)
+		if err != nil {
+			return err
+		}
+		setMember(marker, "contentHash", &orderedNode{kind: 'v', scalar: hash})
+		if len(g.optional) > 0 {
+			optional := &orderedNode{kind: 'a'}
+			for _, field := range g.optional {
+				optional.values = append(optional.values, &orderedNode{kind: 'v', scalar: field})
+			}
+			setMember(marker, "optional", optional)
+		}
+		setMember(node, regenerateKey, marker)
+	}
+	return nil
+}
+
+// collectRegenerated adds the type hashes of the items written by Regenerate
+// in an item array to hashes, keyed by regenerateItemKey.
+func collectRegenerated(items *orderedNode, hashes map[string]string) {
+	if items == nil || items.kind != 'a' {
+		return
+	}
+	for _, item := range items.values {
+		if children := memberOf(item, "item"); children != nil && memberOf(item, "request") == nil {
+			collectRegenerated(children, hashes)
+			continue
+		}
+		if key, ok := regeneratedKey(item); ok {
+			hash, _ := scalarString(memberOf(memberOf(item, regenerateKey), "typeHash"))
+			hashes[key] = hash
+		}
+	}
+}
+
+// mergeRegenerated merges the rendered items of fresh into the existing item
+// array. Existing items written by Regenerate are replaced by their rebuilt
+// version, kept when they were not rebuilt, or removed when their route is
+// no longer current. Fresh items and folders missing from existing are added
+// at the end of their folder.
+func mergeRegenerated(existing *orderedNode, fresh *orderedNode, current map[string]bool, rebuilt map[string]bool) *orderedNode {
+	freshItems := map[string]int{}
+	freshFolders := map[string]int{}
+	for i, item := range fresh.values {
+		if key, ok := regeneratedKey(item); ok {
+			freshItems[key] = i
+		} else if name, ok := scalarString(memberOf(item, "name")); ok {
+			freshFolders[name] = i
+		}
+	}
+
+	merged := &orderedNode{kind: 'a'}
+	used := map[int]bool{}
+	if existing != nil && existing.kind == 'a' {
+		for _, item := range existing.values {
+			if children := memberOf(item, "item"); children != nil && memberOf(item, "request") == nil {
+				name, _ := scalarString(memberOf(item, "name"))
+				freshChildren := &orderedNode{kind: 'a'}
+				if i, ok := freshFolders[name]; ok && !used[i] {
+					freshChildren = memberOf(fresh.values[i], "item")
+					used[i] = true
+				}
+				mergedChildren := mergeRegenerated(children, freshChildren, current, rebuilt)
+				if len(mergedChildren.values) == 0 && len(children.values) > 0 {
+					continue
+				}
+				setMember(item, "item", mergedChildren)
+				merged.values = append(merged.values, item)
+				continue
+			}
+
+			key, ok := regeneratedKey(item)
+			switch {
+			case !ok:
+				// Items generated before they were marked, e.g. by Write,
+				// are replaced by the fresh item of the same request.
+				if i := slices.IndexFunc(fresh.values, func(f *orderedNode) bool { return sameRequestItem(item, f) }); i >= 0 && !used[i] {
+					item = fresh.values[i]
+					used[i] = true
+				}
+				merged.values = append(merged.values, item)
+			case !current[key]:
+			case rebuilt[key]:
+				if i, ok := freshItems[key]; ok && !used[i] {
+					merged.values = append(merged.values, fresh.values[i])
+					used[i] = true
+				}
+			default:
+				merged.values = append(merged.values, item)
+			}
+		}
+	}
+
+	for i, item := range fresh.values {
+		if !used[i] {
+			merged.values = append(merged.values, item)
+		}
+	}
+	return merged
+}
+
+// sameRequestItem reports whether two item nodes have the same name, method
+// and URL.
+func sameRequestItem(a *orderedNode, b *orderedNode) bool {
+	requestA, requestB := memberOf(a, "request"), memberOf(b, "request")
+	if requestA == nil || requestB == nil {
+		return false
+	}
+	nameA, _ := scalarString(memberOf(a, "name"))
+	nameB, _ := scalarString(memberOf(b, "name"))
+	methodA, _ := scalarString(memberOf(requestA, "method"))
+	methodB, _ := scalarString(memberOf(requestB, "method"))
+	return nameA == nameB && methodA == methodB && rawURL(memberOf(requestA, "url")) == rawURL(memberOf(requestB, "url"))
+}
+
+// rawURL returns the raw form of a request URL node.
+func rawURL(node *orderedNode) string {
+	if raw, ok := scalarString(node); ok {
+		return raw
+	}
+	raw, _ := scalarString(memberOf(node, "raw"))
+	return raw
+}
+
+// regeneratedKey returns the regenerateItemKey of an item written by
+// Regenerate.
+func regeneratedKey(item *orderedNode) (string, bool) {
+	endpoint, ok := scalarString(memberOf(memberOf(item, regenerateKey), "endpoint"))
+	if !ok {
+		return "", false
+	}
+	name, _ := scalarString(memberOf(item, "name"))
+	return regenerateItemKey(endpoint, name), true
+}
+
+// regenerateItemKey identifies an item by its endpoint and name, as the items
+// of a route, such as its negative tests, share the endpoint.
+func regenerateItemKey(endpoint string, name string) string {
+	return endpoint + "\n" + name
+}
+
+// memberOf returns the member key of an object node, or nil.
+func memberOf(node *orderedNode, key string) *orderedNode {
+	if node == nil || node.kind != 'o' {
+		return nil
+	}
+	for i, k := range node.keys {
+		if k == key {
+			return node.values[i]
+		}
+	}
+	return nil
+}
+
+// setMember sets the member key of an object node, adding it at the end if
+// it does not exist.
+func setMember(node *orderedNode, key string, value *orderedNode) {
+	for i, k := range node.keys {
+		if k == key {
+			node.values[i] = value
+			return
+		}
+	}
+	node.keys = append(node.keys, key)
+	node.values = append(node.values, value)
+}
+
+// scalarString returns the value of a string node.
+func scalarString(node *orderedNode) (string, bool) {
+	if node == nil || node.kind != 'v' {
+		return "", false
+	}
+	s, ok := node.scalar.(string)
+	return s, ok
+}
@@ -0,0 +1,149 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// usersAndAdmins returns a generator with POST /users taking users and, if
+// admins is not nil, POST /admins taking admins.
+func usersAndAdmins(t *testing.T, users any, admins any) *PostmanGen {
+	t.Helper()
+	p := generatorWithRoutes(t, users, "/users")
+	if admins != nil {
+		if err := p.Register(RouteSpec{"method": "POST", "path": "/admins", "inputType": reflect.TypeOf(admins)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func TestRegenerate(t *testing.T) {
+	// The existing collection is written by Regenerate, then the admins item
+	// is edited and a request is added by hand.
+	base := usersAndAdmins(t, diffUser{}, diffUser{})
+	written, err := base.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	regenerated, err := base.Regenerate(nil, bytes.NewReader(written))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := decodeOrdered(json.NewDecoder(bytes.NewReader(regenerated)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := memberOf(doc, "item")
+	setMember(regeneratedItem(t, items, "admins"), "description", &orderedNode{kind: 'v', scalar: "Edited by hand."})
+	handmade, err := decodeOrdered(json.NewDecoder(strings.NewReader(`{"name": "health", "request": {"method": "GET", "url": "{{base_url}}/health"}}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items.values = append(items.values, handmade)
+	existing, err := encodeCollection(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		current *PostmanGen
+		changed []reflect.Type
+		// wantEmail is set when the users item is rebuilt with the email
+		// field, wantEdited when the admins item keeps its edit.
+		wantEmail  bool
+		wantEdited bool
+		wantAdmins bool
+	}{
+		{
+			name:       "unchanged",
+			current:    usersAndAdmins(t, diffUser{}, diffUser{}),
+			wantEdited: true,
+			wantAdmins: true,
+		},
+		{
+			name:       "type changed",
+			current:    usersAndAdmins(t, diffUserWithEmail{}, diffUser{}),
+			wantEmail:  true,
+			wantEdited: true,
+			wantAdmins: true,
+		},
+		{
+			name:       "edited item stale",
+			current:    usersAndAdmins(t, diffUser{}, diffUserWithEmail{}),
+			wantAdmins: true,
+		},
+		{
+			name:       "changed type listed",
+			current:    usersAndAdmins(t, diffUser{}, diffUser{}),
+			changed:    []reflect.Type{reflect.TypeOf(diffUser{})},
+			wantAdmins: true,
+		},
+		{
+			name:    "route removed",
+			current: usersAndAdmins(t, diffUser{}, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := tt.current.Regenerate(tt.changed, bytes.NewReader(existing))
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := decodeOrdered(json.NewDecoder(bytes.NewReader(merged)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			items := memberOf(doc, "item")
+
+			users := regeneratedItem(t, items, "users")
+			body, _ := scalarString(memberOf(memberOf(memberOf(users, "request"), "body"), "raw"))
+			if got := strings.Contains(body, `"email"`); got != tt.wantEmail {
+				t.Errorf("users body has email = %v, want %v:\n%s", got, tt.wantEmail, body)
+			}
+
+			admins := findItem(items, "admins")
+			if (admins != nil) != tt.wantAdmins {
+				t.Fatalf("admins item present = %v, want %v", admins != nil, tt.wantAdmins)
+			}
+			if admins != nil {
+				description, _ := scalarString(memberOf(admins, "description"))
+				if got := description == "Edited by hand."; got != tt.wantEdited {
+					t.Errorf("admins item keeps its edit = %v, want %v", got, tt.wantEdited)
+				}
+			}
+
+			if findItem(items, "health") == nil {
+				t.Error("request added by hand was removed")
+			}
+		})
+	}
+}
+
+// findItem returns the item named name in an item array, or nil.
+func findItem(items *orderedNode, name string) *orderedNode {
+	for _, item := range items.values {
+		if itemName, _ := scalarString(memberOf(item, "name")); itemName == name {
+			return item
+		}
+	}
+	return nil
+}
+
+// regeneratedItem returns the item named name, failing the test if it is
+// missing or not marked by Regenerate.
+func regeneratedItem(t *testing.T, items *orderedNode, name string) *orderedNode {
+	t.Helper()
+	item := findItem(items, name)
+	if item == nil {
+		t.Fatalf("item %q missing", name)
+	}
+	if _, ok := regeneratedKey(item); !ok {
+		t.Fatalf("item %q not marked by Regenerate", name)
+	}
+	return item
+}
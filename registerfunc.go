@@ -0,0 +1,54 @@
+package postmangen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+var (
+	contextType        = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+	requestType        = reflect.TypeOf(&http.Request{})
+	responseWriterType = reflect.TypeOf((*http.ResponseWriter)(nil)).Elem()
+)
+
+// RegisterFunc registers a route whose input and response types are inferred
+// from the signature of handler, e.g.
+// func(context.Context, CreateUserRequest) (UserResponse, error). The input
+// type is the first parameter that is not a context.Context, *http.Request or
+// http.ResponseWriter, and the response type is the first result that is not
+// an error. Handlers without an input parameter register an empty input.
+func (p *PostmanGen) RegisterFunc(method string, path string, handler any) error {
+	t := reflect.TypeOf(handler)
+	if t == nil || t.Kind() != reflect.Func {
+		return errors.New("invalid handler: must be a function")
+	}
+
+	spec := map[string]any{
+		"method":    method,
+		"path":      path,
+		"inputType": reflect.TypeOf(struct{}{}),
+	}
+
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if in == requestType || in.Implements(contextType) || in.Implements(responseWriterType) {
+			continue
+		}
+		spec["inputType"] = in
+		break
+	}
+
+	for i := 0; i < t.NumOut(); i++ {
+		out := t.Out(i)
+		if out.Implements(errorType) {
+			continue
+		}
+		spec["responseType"] = out
+		break
+	}
+
+	return p.Register(spec)
+}
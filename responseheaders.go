@@ -0,0 +1,73 @@
+package postmangen
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// specResponseHeaders returns the headers of the status response declared by
+// the "responseHeaders" spec key. The key holds either a map[string]string
+// applying to every response of the route, or a map[int]map[string]string
+// keyed by status.
+func specResponseHeaders(spec map[string]any, status int) map[string]string {
+	switch v := spec["responseHeaders"].(type) {
+	case map[string]string:
+		return v
+	case map[int]map[string]string:
+		return v[status]
+	}
+	return nil
+}
+
+// addResponseHeaders appends headers to the saved response in key order.
+func addResponseHeaders(response *postman.Response, headers map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(headers)) {
+		response.Headers.Headers = append(response.Headers.Headers, &postman.Header{Key: key, Value: headers[key]})
+	}
+}
+
+// specResponseCookies returns the cookies set by the status response declared
+// by the "responseCookies" spec key, a []*http.Cookie set by every response of
+// the route or a map[int][]*http.Cookie keyed by status.
+func specResponseCookies(spec map[string]any, status int) []*http.Cookie {
+	switch v := spec["responseCookies"].(type) {
+	case []*http.Cookie:
+		return v
+	case map[int][]*http.Cookie:
+		return v[status]
+	}
+	return nil
+}
+
+// addResponseCookies documents cookies on the saved response, as Set-Cookie
+// headers carrying every attribute and as Postman cookies.
+func addResponseCookies(response *postman.Response, cookies []*http.Cookie) {
+	for _, c := range cookies {
+		response.Headers.Headers = append(response.Headers.Headers, &postman.Header{Key: "Set-Cookie", Value: c.String()})
+
+		// The Secure and SameSite attributes are only carried by the header,
+		// the cookie format of the collection library has no field for them.
+		cookie := &postman.Cookie{
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Name:     c.Name,
+			Value:    c.Value,
+			HTTPOnly: c.HttpOnly,
+			Session:  c.Expires.IsZero() && c.MaxAge == 0,
+		}
+		if cookie.Path == "" {
+			cookie.Path = "/"
+		}
+		if !c.Expires.IsZero() {
+			cookie.Expires = c.Expires.UTC().Format(http.TimeFormat)
+		}
+		if c.MaxAge != 0 {
+			cookie.MaxAge = strconv.Itoa(c.MaxAge)
+		}
+		response.Cookies = append(response.Cookies, cookie)
+	}
+}
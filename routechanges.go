@@ -0,0 +1,54 @@
+package postmangen
+
+import "github.com/rbretecher/go-postman-collection"
+
+// routeChanges are the collection variables, data file values and disabled
+// query params recorded while a route is registered. They are applied only
+// once the route is registered, so a failing Register leaves p unchanged.
+type routeChanges struct {
+	variables     []*postman.Variable
+	dataColumns   []string
+	dataRow       map[string]string
+	disabledQuery map[*postman.QueryParam]bool
+}
+
+func newRouteChanges() *routeChanges {
+	return &routeChanges{
+		dataRow:       map[string]string{},
+		disabledQuery: map[*postman.QueryParam]bool{},
+	}
+}
+
+// applyRouteChanges applies the changes recorded by the route being
+// registered.
+func (p *PostmanGen) applyRouteChanges() {
+	changes := p.pending
+	if changes == nil {
+		return
+	}
+	p.pending = nil
+	for _, v := range changes.variables {
+		p.addPathParamVariable(v.Key, v.Value, v.Description)
+	}
+	for _, column := range changes.dataColumns {
+		p.recordDataValue(column, changes.dataRow[column])
+	}
+	for param := range changes.disabledQuery {
+		p.disabledQuery[param] = true
+	}
+}
+
+// isDisabledQuery reports whether q is emitted disabled, including by the
+// route being registered.
+func (p *PostmanGen) isDisabledQuery(q *postman.QueryParam) bool {
+	return p.disabledQuery[q] || p.pending != nil && p.pending.disabledQuery[q]
+}
+
+// disableQuery makes q emitted disabled.
+func (p *PostmanGen) disableQuery(q *postman.QueryParam) {
+	if p.pending != nil {
+		p.pending.disabledQuery[q] = true
+		return
+	}
+	p.disabledQuery[q] = true
+}
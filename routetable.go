@@ -0,0 +1,201 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// AddTypes makes types available to route tables by name, e.g.
+// AddTypes(map[string]reflect.Type{"User": reflect.TypeOf(User{})}).
+func (p *PostmanGen) AddTypes(types map[string]reflect.Type) *PostmanGen {
+	for name, t := range types {
+		p.namedTypes[name] = t
+	}
+	return p
+}
+
+// RegisterRouteTable registers the routes of a route table, a JSON or YAML
+// list of specs such as
+//
+//	[{"method": "POST", "path": "/users", "inputType": "CreateUserRequest",
+//	  "responseType": "User", "responseStatus": 201}]
+//
+// The "inputType" and "responseType" keys and the values of "responses",
+// keyed by status, name types added with AddTypes. Routes without an
+// inputType have an empty input. Failures are handled like in RegisterAll.
+func (p *PostmanGen) RegisterRouteTable(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '[' {
+		if data, err = YAMLToJSON(data); err != nil {
+			return fmt.Errorf("invalid route table: %w", err)
+		}
+	}
+
+	var table []map[string]any
+	if err := json.Unmarshal(data, &table); err != nil {
+		return fmt.Errorf("invalid route table: %w", err)
+	}
+
+	errs := []error{}
+	specs := []RouteSpec{}
+	for i, entry := range table {
+		spec, err := p.tableSpec(entry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("route %d (%v %v): %w", i+1, entry["method"], entry["path"], err))
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	return errors.Join(append(errs, p.RegisterAll(specs...))...)
+}
+
+// tableSpec converts a route table entry to a spec, resolving type names and
+// turning JSON arrays and objects of strings into the Go types Register
+// expects.
+func (p *PostmanGen) tableSpec(entry map[string]any) (RouteSpec, error) {
+	spec := RouteSpec{"inputType": reflect.TypeOf(struct{}{})}
+	for key, value := range entry {
+		switch key {
+		case "inputType", "responseType":
+			t, err := p.namedType(value)
+			if err != nil {
+				return nil, err
+			}
+			spec[key] = t
+		case "responses":
+			statuses, ok := value.(map[string]any)
+			if !ok {
+				return nil, errors.New("invalid responses: must map statuses to type names")
+			}
+			responses := map[int]reflect.Type{}
+			for status, name := range statuses {
+				code, err := strconv.Atoi(status)
+				if err != nil {
+					return nil, fmt.Errorf("invalid response status %q", status)
+				}
+				if name == nil {
+					responses[code] = nil
+					continue
+				}
+				if responses[code], err = p.namedType(name); err != nil {
+					return nil, err
+				}
+			}
+			spec[key] = responses
+		case "protocolProfileBehavior":
+			settings, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s: must be an object of booleans, got %s", key, tableValueName(value))
+			}
+			behavior := map[string]bool{}
+			for name, enabled := range settings {
+				if behavior[name], ok = enabled.(bool); !ok {
+					return nil, fmt.Errorf("invalid %s: %s must be a boolean, got %s", key, name, tableValueName(enabled))
+				}
+			}
+			spec[key] = behavior
+		default:
+			value = tableValue(value)
+			if want, ok := tableValueTypes[key]; ok && reflect.TypeOf(value) != want.typ {
+				return nil, fmt.Errorf("invalid %s: must be %s, got %s", key, want.name, tableValueName(value))
+			}
+			spec[key] = value
+		}
+	}
+	return spec, nil
+}
+
+// tableValueTypes are the types Register reads spec keys as, after
+// conversion by tableValue. Values of other types would be ignored, so they
+// are rejected.
+var tableValueTypes = map[string]struct {
+	typ  reflect.Type
+	name string
+}{
+	"method":            {reflect.TypeOf(""), "a string"},
+	"path":              {reflect.TypeOf(""), "a string"},
+	"name":              {reflect.TypeOf(""), "a string"},
+	"folder":            {reflect.TypeOf(""), "a string"},
+	"fragment":          {reflect.TypeOf(""), "a string"},
+	"body":              {reflect.TypeOf(""), "a string"},
+	"bodyLanguage":      {reflect.TypeOf(""), "a string"},
+	"baseURLVariable":   {reflect.TypeOf(""), "a string"},
+	"boundaryExamples":  {reflect.TypeOf(false), "a boolean"},
+	"headAndOptions":    {reflect.TypeOf(false), "a boolean"},
+	"negativeTests":     {reflect.TypeOf(false), "a boolean"},
+	"responseEnvelope":  {reflect.TypeOf(false), "a boolean"},
+	"sse":               {reflect.TypeOf(false), "a boolean"},
+	"maxResponseTimeMs": {reflect.TypeOf(0.0), "a number"},
+	"responseStatus":    {reflect.TypeOf(0.0), "a number"},
+	"produces":          {reflect.TypeOf([]string{}), "a list of strings"},
+	"headers":           {reflect.TypeOf(map[string]string{}), "an object of strings"},
+	"query":             {reflect.TypeOf(map[string]string{}), "an object of strings"},
+	"extract":           {reflect.TypeOf(map[string]string{}), "an object of strings"},
+}
+
+// tableValueName describes the type of a decoded route table value.
+func tableValueName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case []string, []any:
+		return "a list"
+	}
+	return "an object"
+}
+
+// namedType returns the type added with AddTypes under name.
+func (p *PostmanGen) namedType(name any) (reflect.Type, error) {
+	s, ok := name.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid type name %v: must be a string", name)
+	}
+	t, ok := p.namedTypes[s]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q: add it with AddTypes", s)
+	}
+	return t, nil
+}
+
+// tableValue converts lists and objects whose values are all strings to
+// []string and map[string]string, as used by keys such as "headers" and
+// "produces". Other values are kept as decoded.
+func tableValue(value any) any {
+	switch v := value.(type) {
+	case []any:
+		strs := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return value
+			}
+			strs = append(strs, s)
+		}
+		return strs
+	case map[string]any:
+		strs := make(map[string]string, len(v))
+		for key, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return value
+			}
+			strs[key] = s
+		}
+		return strs
+	}
+	return value
+}
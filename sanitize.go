@@ -0,0 +1,86 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Sanitizer rewrites a recorded request or response body before it is saved
+// in the collection, e.g. to mask personal data.
+type Sanitizer func(body string) string
+
+// AddSanitizer adds a sanitizer applied to the bodies of the exchanges saved
+// by Recorder and RecordProxy. Sanitizers run in the order they are added.
+func (p *PostmanGen) AddSanitizer(sanitizers ...Sanitizer) *PostmanGen {
+	p.sanitizers = append(p.sanitizers, sanitizers...)
+	return p
+}
+
+// sanitizeBody applies the sanitizers to a recorded body.
+func (p *PostmanGen) sanitizeBody(body string) string {
+	for _, sanitize := range p.sanitizers {
+		body = sanitize(body)
+	}
+	return body
+}
+
+var emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// MaskEmails masks the local part of email addresses but its first
+// character, e.g. jane.doe@example.com becomes j***@example.com.
+func MaskEmails() Sanitizer {
+	return func(body string) string {
+		return emailPattern.ReplaceAllString(body, "$1***@$2")
+	}
+}
+
+// RedactPattern replaces the matches of pattern with replacement, which may
+// refer to submatches like regexp.Regexp.ReplaceAllString.
+func RedactPattern(pattern *regexp.Regexp, replacement string) Sanitizer {
+	return func(body string) string {
+		return pattern.ReplaceAllString(body, replacement)
+	}
+}
+
+// RedactJSONFields replaces the values of the members of JSON bodies named
+// keys, at any depth, with "<redacted>", e.g. RedactJSONFields("token",
+// "password"). Keys are matched case-insensitively. Bodies that are not JSON
+// are returned unchanged.
+func RedactJSONFields(keys ...string) Sanitizer {
+	lower := make([]string, len(keys))
+	for i, key := range keys {
+		lower[i] = strings.ToLower(key)
+	}
+
+	var redact func(node *orderedNode) bool
+	redact = func(node *orderedNode) bool {
+		changed := false
+		for i, value := range node.values {
+			if node.kind == 'o' && slices.Contains(lower, strings.ToLower(node.keys[i])) {
+				node.values[i] = &orderedNode{kind: 'v', scalar: redactedValue}
+				changed = true
+				continue
+			}
+			if redact(value) {
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	return func(body string) string {
+		dec := json.NewDecoder(strings.NewReader(body))
+		node, err := decodeOrdered(dec)
+		if err != nil || dec.More() || !redact(node) {
+			return body
+		}
+		buf := &bytes.Buffer{}
+		if err := writeOrderedJSON(buf, node); err != nil {
+			return body
+		}
+		return buf.String()
+	}
+}
@@ -0,0 +1,363 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// WriteJSONSchemas writes a JSON Schema (draft-07) file per registered request
+// and response struct into dir, named after the Go type. Request schemas
+// describe the JSON body fields; response schemas follow encoding/json. The
+// `description`, `example` and `enum:"a,b,c"` tags are honored, as well as the
+// required, min, max, len, oneof and email rules of `validate` tags. A type
+// used both as a request and as a response gets both schemas, the second
+// named with a numeric suffix, e.g. User.schema.json and User2.schema.json.
+// A type named like a type of another package written before is qualified
+// by its package path, e.g. github.com.acme.billing.User.schema.json.
+func (p *PostmanGen) WriteJSONSchemas(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// Inputs and responses are tracked apart: the request schema of a type
+	// only covers its body fields, so it does not stand in for its response
+	// schema.
+	writtenInputs := map[reflect.Type]bool{}
+	writtenResponses := map[reflect.Type]bool{}
+	usedNames := map[string]int{}
+	nameTypes := map[string]reflect.Type{}
+
+	write := func(t reflect.Type, schema map[string]any) error {
+		name := t.Name()
+		if name == "" {
+			name = "Anonymous"
+		} else if owner, ok := nameTypes[name]; ok && owner != t {
+			name = qualifiedTypeName(t)
+		} else {
+			nameTypes[name] = t
+		}
+		usedNames[name]++
+		if n := usedNames[name]; n > 1 {
+			name = fmt.Sprintf("%s%d", name, n)
+		}
+
+		b, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, name+".schema.json"), b, 0o644)
+	}
+
+	for _, r := range p.routes {
+		inputType := derefType(r.inputType)
+		if !writtenInputs[inputType] {
+			writtenInputs[inputType] = true
+			if schema, ok := p.requestBodySchema(inputType); ok {
+				if err := write(inputType, schema); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, resp := range r.responses {
+			if resp.typ == nil {
+				continue
+			}
+			responseType := derefType(resp.typ)
+			if writtenResponses[responseType] {
+				continue
+			}
+			writtenResponses[responseType] = true
+			if err := write(responseType, p.rootSchema(responseType)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestBodySchema returns the schema of the JSON body fields of a request
+// struct, or false if it has none.
+func (p *PostmanGen) requestBodySchema(t reflect.Type) (map[string]any, bool) {
+	defs := newSchemaDefinitions()
+	properties := map[string]any{}
+	required := []string{}
+
+	walkOwnedStructFields(t, func(owner reflect.Type, field reflect.StructField) {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return
+		}
+		properties[name] = p.fieldSchema(owner, field, defs)
+		if fieldValidateRules(field).Required {
+			required = append(required, name)
+		}
+	})
+
+	if len(properties) == 0 {
+		return nil, false
+	}
+
+	schema := map[string]any{
+		"$schema":    jsonSchemaDraft,
+		"title":      t.Name(),
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(defs.schemas) > 0 {
+		schema["definitions"] = defs.schemas
+	}
+	return schema, true
+}
+
+// rootSchema returns a standalone schema document for t.
+func (p *PostmanGen) rootSchema(t reflect.Type) map[string]any {
+	defs := newSchemaDefinitions()
+
+	schema := p.structSchema(t, defs)
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = t.Name()
+	if len(defs.schemas) > 0 {
+		schema["definitions"] = defs.schemas
+	}
+	return schema
+}
+
+// schemaDefinitions are the definitions of a schema document, one per named
+// struct. A struct named like a struct of another package defined before is
+// qualified by its package path.
+type schemaDefinitions struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+func newSchemaDefinitions() *schemaDefinitions {
+	return &schemaDefinitions{schemas: map[string]any{}, names: map[reflect.Type]string{}}
+}
+
+// name returns the definition name of t, and whether t is defined already.
+func (d *schemaDefinitions) name(t reflect.Type) (string, bool) {
+	if name, ok := d.names[t]; ok {
+		return name, true
+	}
+	name := t.Name()
+	if _, taken := d.schemas[name]; taken {
+		name = qualifiedTypeName(t)
+	}
+	d.names[t] = name
+	return name, false
+}
+
+// qualifiedTypeName returns the name of t prefixed by its package path, with
+// dots instead of slashes so it can be used in file names and $ref pointers,
+// e.g. "github.com.acme.billing.User".
+func qualifiedTypeName(t reflect.Type) string {
+	return strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+}
+
+// typeSchema returns the schema of t, registering named structs in defs and
+// referring to them.
+func (p *PostmanGen) typeSchema(t reflect.Type, defs *schemaDefinitions) map[string]any {
+	t = derefType(t)
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]any{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": p.typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": p.typeSchema(t.Elem(), defs)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return p.structSchema(t, defs)
+		}
+		name, defined := defs.name(t)
+		if !defined {
+			// Reserve the name first so recursive types terminate.
+			defs.schemas[name] = map[string]any{}
+			defs.schemas[name] = p.structSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/definitions/" + name}
+	case reflect.Interface:
+		return map[string]any{}
+	}
+
+	if p.int64AsString && (t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64) {
+		return map[string]any{"type": "string", "pattern": "^-?[0-9]+$"}
+	}
+	if isNumberKind(t.Kind()) {
+		return map[string]any{"type": "integer"}
+	}
+	return map[string]any{}
+}
+
+// structSchema returns the object schema of struct t following encoding/json
+// field naming and embedding rules.
+func (p *PostmanGen) structSchema(t reflect.Type, defs *schemaDefinitions) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if f.Anonymous && name == "" && derefType(f.Type).Kind() == reflect.Struct {
+				collect(derefType(f.Type))
+				continue
+			}
+			if f.PkgPath != "" {
+				continue
+			}
+			if name == "" {
+				name = p.untaggedName(f.Name)
+			}
+
+			properties[name] = p.fieldSchema(t, f, defs)
+			if fieldValidateRules(f).Required {
+				required = append(required, name)
+			}
+		}
+	}
+	collect(t)
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if description := p.translate(p.typeDoc(t)); description != "" {
+		schema["description"] = description
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// fieldSchema returns the schema of a struct field, annotated from its tags.
+func (p *PostmanGen) fieldSchema(owner reflect.Type, f reflect.StructField, defs *schemaDefinitions) map[string]any {
+	schema := p.typeSchema(f.Type, defs)
+	if _, isRef := schema["$ref"]; isRef {
+		// Keywords next to $ref are ignored by draft-07 validators.
+		schema = map[string]any{"allOf": []any{schema}}
+	}
+
+	if description := p.fieldDescription(owner, f); description != "" {
+		schema["description"] = description
+	}
+	if example := f.Tag.Get("example"); example != "" && example != "-" {
+		schema["examples"] = []any{coerceExample(example, f.Type)}
+	}
+	if enum := f.Tag.Get("enum"); enum != "" {
+		schema["enum"] = enumValues(strings.Split(enum, ","), f.Type)
+	}
+
+	rules := fieldValidateRules(f)
+	if p.encodedAsString(f) {
+		schema["type"] = "string"
+		if isNumberKind(derefType(f.Type).Kind()) && !isFloatKind(derefType(f.Type).Kind()) {
+			schema["pattern"] = "^-?[0-9]+$"
+		}
+		if examples, ok := schema["examples"].([]any); ok {
+			schema["examples"] = []any{stringEncoded(examples[0], f.Type)}
+		}
+	} else {
+		if len(rules.OneOf) > 0 {
+			schema["enum"] = enumValues(rules.OneOf, f.Type)
+		}
+		if rules.Email {
+			schema["format"] = "email"
+		}
+		if rules.Pattern != "" {
+			schema["pattern"] = rules.Pattern
+		}
+	}
+
+	// The keywords follow the Go kind the rules apply to rather than the
+	// JSON type, so a number encoded as a string keeps its range.
+	minKey, maxKey := "minimum", "maximum"
+	switch t := derefType(f.Type); t.Kind() {
+	case reflect.String:
+		minKey, maxKey = "minLength", "maxLength"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			minKey, maxKey = "minLength", "maxLength"
+		} else {
+			minKey, maxKey = "minItems", "maxItems"
+		}
+	case reflect.Map:
+		minKey, maxKey = "minProperties", "maxProperties"
+	}
+	if rules.Len != nil {
+		schema[minKey] = *rules.Len
+		schema[maxKey] = *rules.Len
+	}
+	if rules.Min != nil {
+		if rules.MinExclusive && minKey == "minimum" {
+			schema["exclusiveMinimum"] = *rules.Min
+		} else if rules.MinExclusive {
+			schema[minKey] = *rules.Min + 1
+		} else {
+			schema[minKey] = *rules.Min
+		}
+	}
+	if rules.Max != nil {
+		if rules.MaxExclusive && maxKey == "maximum" {
+			schema["exclusiveMaximum"] = *rules.Max
+		} else if rules.MaxExclusive {
+			schema[maxKey] = *rules.Max - 1
+		} else {
+			schema[maxKey] = *rules.Max
+		}
+	}
+
+	return schema
+}
+
+func enumValues(values []string, t reflect.Type) []any {
+	enum := make([]any, len(values))
+	for i, v := range values {
+		enum[i] = coerceExample(strings.TrimSpace(v), t)
+	}
+	return enum
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
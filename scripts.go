@@ -0,0 +1,202 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"text/template"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// Script files are Go templates delimited by {% and %}, which leave the
+// {{variable}} references of Postman and JavaScript's ${...} alone, e.g.
+//
+//	pm.test("Status is {% .status %}", function () {
+//	    pm.response.to.have.status({% .status %});
+//	});
+const (
+	scriptLeftDelim  = "{%"
+	scriptRightDelim = "%}"
+)
+
+// WithScriptFS makes script files be read from fsys, such as an embed.FS,
+// instead of the working directory.
+func WithScriptFS(fsys fs.FS) Option {
+	return func(p *PostmanGen) {
+		p.scriptFS = fsys
+	}
+}
+
+// WithScriptData sets the data script file templates are executed with.
+// Route scripts also get the route being registered as .route, a RouteInfo.
+func WithScriptData(data map[string]any) Option {
+	return func(p *PostmanGen) {
+		p.scriptData = data
+	}
+}
+
+// WithTestScriptFile appends the script file name to the collection-level
+// test script. The file is read when the collection is written, so a missing
+// file or invalid template is reported by Write.
+func WithTestScriptFile(name string) Option {
+	return func(p *PostmanGen) {
+		p.scriptFiles = append(p.scriptFiles, scriptFile{listen: postman.Test, name: name})
+	}
+}
+
+// WithPreRequestScriptFile appends the script file name to the
+// collection-level prerequest script, see WithTestScriptFile.
+func WithPreRequestScriptFile(name string) Option {
+	return func(p *PostmanGen) {
+		p.scriptFiles = append(p.scriptFiles, scriptFile{listen: postman.PreRequest, name: name})
+	}
+}
+
+// scriptFile is a collection-level script file, or script template when
+// template is set.
+type scriptFile struct {
+	listen   postman.ListenType
+	name     string
+	template *TemplateScript
+}
+
+// loadScript reads the script file name and executes it as a template with
+// the script data, extended with extra.
+func (p *PostmanGen) loadScript(name string, extra map[string]any) ([]string, error) {
+	fsys := p.scriptFS
+	if fsys == nil {
+		fsys = os.DirFS(".")
+	}
+	source, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+
+	data := maps.Clone(p.scriptData)
+	if data == nil {
+		data = map[string]any{}
+	}
+	maps.Copy(data, extra)
+	script, err := executeScript(name, string(source), data)
+	if err != nil {
+		return nil, err
+	}
+	return []string{script}, nil
+}
+
+// executeScript executes the script template source with data. Besides the
+// builtin functions, templates can call js, which writes a value as a
+// JavaScript literal, e.g. {% js .variable %}.
+func executeScript(name string, source string, data map[string]any) (string, error) {
+	tmpl, err := template.New(name).
+		Delims(scriptLeftDelim, scriptRightDelim).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"js": jsValue}).
+		Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid script %s: %w", name, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("invalid script %s: %w", name, err)
+	}
+	return string(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
+
+// jsValue returns v as a JavaScript literal.
+func jsValue(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// specScriptFiles returns the script file names of the spec key, a string
+// or a []string. Empty names are skipped.
+func specScriptFiles(spec map[string]any, key string) []string {
+	switch v := spec[key].(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []string:
+		return slices.DeleteFunc(slices.Clone(v), func(name string) bool {
+			return name == ""
+		})
+	}
+	return nil
+}
+
+// routeScriptFiles appends the script files of the "preRequestScriptFile" and
+// "testScriptFile" spec keys and the template scripts of the "testTemplates"
+// spec key to events.
+func (p *PostmanGen) routeScriptFiles(events []*postman.Event, spec map[string]any) ([]*postman.Event, error) {
+	keys := []struct {
+		listen postman.ListenType
+		key    string
+	}{{postman.PreRequest, "preRequestScriptFile"}, {postman.Test, "testScriptFile"}}
+	for _, file := range keys {
+		for _, name := range specScriptFiles(spec, file.key) {
+			extra := map[string]any{}
+			if p.currentRoute != nil {
+				extra["route"] = *p.currentRoute
+			}
+			lines, err := p.loadScript(name, extra)
+			if err != nil {
+				return nil, err
+			}
+			events = appendScript(events, file.listen, lines...)
+		}
+	}
+	for _, script := range specTemplateScripts(spec) {
+		lines, err := p.templateScript(script)
+		if err != nil {
+			return nil, err
+		}
+		events = appendScript(events, postman.Test, lines...)
+	}
+	return events, nil
+}
+
+// addScriptFiles adds the collection-level script files to the collection
+// events and returns a function restoring them.
+func (p *PostmanGen) addScriptFiles() (func(), error) {
+	events := p.collection.Events
+	restore := func() {
+		p.collection.Events = events
+	}
+	if len(p.scriptFiles) == 0 {
+		return restore, nil
+	}
+
+	// The events are copied, as appendScript extends existing scripts in
+	// place.
+	withFiles := []*postman.Event{}
+	for _, event := range events {
+		copied := *event
+		if event.Script != nil {
+			script := *event.Script
+			script.Exec = append([]string{}, event.Script.Exec...)
+			copied.Script = &script
+		}
+		withFiles = append(withFiles, &copied)
+	}
+	for _, file := range p.scriptFiles {
+		var lines []string
+		var err error
+		if file.template != nil {
+			lines, err = p.templateScript(*file.template)
+		} else {
+			lines, err = p.loadScript(file.name, nil)
+		}
+		if err != nil {
+			return restore, err
+		}
+		withFiles = appendScript(withFiles, file.listen, lines...)
+	}
+	p.collection.Events = withFiles
+	return restore, nil
+}
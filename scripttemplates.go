@@ -0,0 +1,115 @@
+package postmangen
+
+import (
+	"embed"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+//go:embed templates/*.js
+var templateFiles embed.FS
+
+// TemplateScript is a script rendered from the script template library by
+// name, with Params as the template data.
+//
+// The library ships these templates:
+//
+//   - "assert-status" asserts that the response has the status param.
+//   - "extract-field" stores the member of the JSON response at path, such
+//     as "data.id", in the collection variable named by the variable param.
+//   - "retry-on-401" runs the request again, up to maxRetries times (1 by
+//     default), when it is rejected with 401, after unsetting the collection
+//     variable named by tokenVariable ("token" by default) so a token refresh
+//     script fetches a new one.
+//
+// AddScriptTemplate adds templates to the library.
+type TemplateScript struct {
+	Name   string
+	Params map[string]any
+}
+
+// templateDefaults are the default params of the templates of the library.
+var templateDefaults = map[string]map[string]any{
+	"retry-on-401": {"maxRetries": 1, "tokenVariable": "token"},
+}
+
+// AssertStatus returns the "assert-status" template script.
+func AssertStatus(status int) TemplateScript {
+	return TemplateScript{Name: "assert-status", Params: map[string]any{"status": status}}
+}
+
+// ExtractField returns the "extract-field" template script.
+func ExtractField(path string, variable string) TemplateScript {
+	return TemplateScript{Name: "extract-field", Params: map[string]any{"path": path, "variable": variable}}
+}
+
+// RetryOn401 returns the "retry-on-401" template script.
+func RetryOn401(maxRetries int) TemplateScript {
+	return TemplateScript{Name: "retry-on-401", Params: map[string]any{"maxRetries": maxRetries}}
+}
+
+// AddScriptTemplate adds the script template source, written like script
+// files (see WithScriptFS), to the library under name, replacing a template
+// shipped with the same name.
+func (p *PostmanGen) AddScriptTemplate(name string, source string) *PostmanGen {
+	p.scriptTemplates[name] = source
+	return p
+}
+
+// WithTestTemplates appends the template scripts to the collection-level test
+// script. They are rendered when the collection is written, so unknown
+// templates and missing params are reported by Write.
+func WithTestTemplates(scripts ...TemplateScript) Option {
+	return func(p *PostmanGen) {
+		for _, script := range scripts {
+			p.scriptFiles = append(p.scriptFiles, scriptFile{listen: postman.Test, name: script.Name, template: &script})
+		}
+	}
+}
+
+// RenderScriptTemplate returns the script of a template script, for use in
+// custom script setups.
+func (p *PostmanGen) RenderScriptTemplate(script TemplateScript) (string, error) {
+	source, ok := p.scriptTemplates[script.Name]
+	if !ok {
+		b, err := templateFiles.ReadFile("templates/" + script.Name + ".js")
+		if err != nil {
+			return "", fmt.Errorf("unknown script template %q", script.Name)
+		}
+		source = string(b)
+	}
+
+	data := maps.Clone(templateDefaults[script.Name])
+	if data == nil {
+		data = map[string]any{}
+	}
+	maps.Copy(data, script.Params)
+	return executeScript(script.Name, source, data)
+}
+
+// templateScript returns the lines of a template script.
+func (p *PostmanGen) templateScript(script TemplateScript) ([]string, error) {
+	s, err := p.RenderScriptTemplate(script)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(s, "\n"), nil
+}
+
+// specTemplateScripts returns the template scripts of the "testTemplates"
+// spec key, followed by an "extract-field" script for each entry of the
+// "extract" spec key, which maps collection variables to paths in the JSON
+// response, e.g. {"user_id": "data.id"}.
+func specTemplateScripts(spec map[string]any) []TemplateScript {
+	scripts, _ := spec["testTemplates"].([]TemplateScript)
+	extract, _ := spec["extract"].(map[string]string)
+	scripts = slices.Clip(scripts)
+	for _, variable := range slices.Sorted(maps.Keys(extract)) {
+		scripts = append(scripts, ExtractField(extract[variable], variable))
+	}
+	return scripts
+}
@@ -0,0 +1,91 @@
+package postmangen
+
+import (
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// WriteOption changes how Write and WriteToFile encode the collection.
+type WriteOption func(*writeSettings)
+
+type writeSettings struct {
+	// secretValue returns the value written for a sensitive variable, nil
+	// when values are written unchanged.
+	secretValue func(key string) string
+	// sharedVariables are added to the collection variables it does not
+	// define itself, see Workspace.
+	sharedVariables []*postman.Variable
+	// contentHashes marks generated items with their content hash, see
+	// WithContentHashes.
+	contentHashes bool
+}
+
+// sensitiveNames are the name fragments that make a variable sensitive
+// without being marked with MarkSensitive.
+var sensitiveNames = []string{"token", "password", "passwd", "secret", "api_key", "apikey", "private_key"}
+
+// MarkSensitive flags collection variables whose values must not be written
+// when MaskSecrets or VaultSecrets is used. Variables with names containing
+// token, password, secret, api_key or private_key are flagged automatically.
+func (p *PostmanGen) MarkSensitive(keys ...string) *PostmanGen {
+	for _, key := range keys {
+		p.sensitiveVariables[key] = true
+	}
+	return p
+}
+
+// MaskSecrets writes sensitive variables with empty values, so generated
+// files are safe to commit. Users fill in the values in Postman.
+func MaskSecrets() WriteOption {
+	return MaskSecretsWith(func(string) string { return "" })
+}
+
+// VaultSecrets writes sensitive variables as {{vault:key}} references to
+// Postman Vault secrets.
+func VaultSecrets() WriteOption {
+	return MaskSecretsWith(func(key string) string { return "{{vault:" + key + "}}" })
+}
+
+// MaskSecretsWith writes sensitive variables with the value returned by
+// replace for their key.
+func MaskSecretsWith(replace func(key string) string) WriteOption {
+	return func(s *writeSettings) {
+		s.secretValue = replace
+	}
+}
+
+// isSensitive reports whether the value of the variable key is a secret.
+func (p *PostmanGen) isSensitive(key string) bool {
+	if p.sensitiveVariables[key] {
+		return true
+	}
+	lower := strings.ToLower(key)
+	for _, name := range sensitiveNames {
+		if strings.Contains(lower, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecrets replaces the values of sensitive collection variables as
+// configured by settings, and returns a function restoring them.
+func (p *PostmanGen) maskSecrets(settings writeSettings) (restore func()) {
+	if settings.secretValue == nil {
+		return func() {}
+	}
+
+	originals := map[int]string{}
+	for i, v := range p.collection.Variables {
+		if p.isSensitive(v.Key) {
+			originals[i] = v.Value
+			v.Value = settings.secretValue(v.Key)
+		}
+	}
+	return func() {
+		for i, value := range originals {
+			p.collection.Variables[i].Value = value
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package postmangen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// SessionAuth configures cookie session authentication: a login request
+// stores a session cookie in Postman's cookie jar, which sends it with the
+// following requests. Empty fields use the defaults noted on each field.
+type SessionAuth struct {
+	// LoginMethod is the method of the login route, "POST" by default.
+	LoginMethod string
+	// LoginPath is the path of the login route as registered, "/login" by
+	// default.
+	LoginPath string
+	// CookieName is the session cookie set by the login response, "session"
+	// by default.
+	CookieName string
+}
+
+// WithSessionAuth replaces the collection's bearer auth with cookie session
+// authentication. Requests carry no Authorization header, and the login route
+// gets a test asserting that its response set the session cookie.
+func WithSessionAuth(cfg SessionAuth) Option {
+	return func(p *PostmanGen) {
+		cfg.LoginMethod = strings.ToUpper(defaultString(cfg.LoginMethod, "POST"))
+		cfg.LoginPath = "/" + strings.Trim(defaultString(cfg.LoginPath, "/login"), "/")
+		cfg.CookieName = defaultString(cfg.CookieName, "session")
+
+		p.sessionAuth = &cfg
+		p.collection.Auth = postman.CreateAuth(postman.NoAuth)
+	}
+}
+
+// isSessionLogin reports whether method and path are the login route of the
+// session auth.
+func (p *PostmanGen) isSessionLogin(method string, path string) bool {
+	return p.sessionAuth != nil && strings.EqualFold(method, p.sessionAuth.LoginMethod) &&
+		"/"+strings.Trim(path, "/") == p.sessionAuth.LoginPath
+}
+
+// sessionLoginDescription documents the login route of the session auth.
+func (p *PostmanGen) sessionLoginDescription() string {
+	return fmt.Sprintf("Send this request first: it stores the `%s` session cookie, which Postman's cookie jar sends with the other requests.", p.sessionAuth.CookieName)
+}
+
+func sessionCookieScript(cookie string) []string {
+	return []string{
+		fmt.Sprintf(`pm.test("Session cookie %s is set", function () {`, cookie),
+		fmt.Sprintf(`    pm.expect(pm.cookies.has(%s)).to.be.true;`, jsString(cookie)),
+		`});`,
+	}
+}
@@ -0,0 +1,38 @@
+package postmangen
+
+import "strings"
+
+// SetKeepTrailingSlash keeps the trailing slash of registered paths in
+// request URLs, e.g. {{base_url}}/users/ for the path "/users/", for servers
+// and gateways that treat /users/ and /users differently. By default it is
+// removed. Items are named and foldered as without the slash.
+func (p *PostmanGen) SetKeepTrailingSlash(enabled bool) *PostmanGen {
+	p.keepTrailingSlash = enabled
+	return p
+}
+
+// SetCollapseSlashes collapses runs of slashes in registered paths, e.g.
+// "/users//:id" to "/users/:id". By default they are kept, giving empty path
+// segments.
+func (p *PostmanGen) SetCollapseSlashes(enabled bool) *PostmanGen {
+	p.collapseSlashes = enabled
+	return p
+}
+
+// normalizePath returns a registered path with runs of slashes collapsed, if
+// enabled by SetCollapseSlashes.
+func (p *PostmanGen) normalizePath(path string) string {
+	if !p.collapseSlashes {
+		return path
+	}
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	return path
+}
+
+// trailingSlash reports whether the request URL of path ends with a slash,
+// as enabled by SetKeepTrailingSlash.
+func (p *PostmanGen) trailingSlash(path string) bool {
+	return p.keepTrailingSlash && strings.HasSuffix(path, "/") && strings.Trim(path, "/") != ""
+}
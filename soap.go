@@ -0,0 +1,187 @@
+package postmangen
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// soapEnvelope wraps the example body of SOAP requests.
+type soapEnvelope struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	Soap    string   `xml:"xmlns:soap,attr"`
+	Body    struct {
+		Content any
+	} `xml:"soap:Body"`
+}
+
+// RegisterSOAP adds a POST item for the SOAP 1.1 endpoint at path with the
+// SOAPAction header set to action. The XML body is an envelope around an
+// example of envelopeType, encoded with encoding/xml; fields use their
+// `example` tag or a default placeholder named after their XML element. If
+// envelopeType is itself named Envelope, it is used as the whole document.
+func (p *PostmanGen) RegisterSOAP(path string, action string, envelopeType reflect.Type) error {
+	if envelopeType == nil || derefType(envelopeType).Kind() != reflect.Struct {
+		return errors.New("invalid envelope type: must be a struct or pointer to struct")
+	}
+
+	body, err := p.soapBody(derefType(envelopeType))
+	if err != nil {
+		return fmt.Errorf("failed to marshal soap envelope: %w", err)
+	}
+
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	baseVariable := p.routeBaseURLVariable(map[string]any{}, pathSegments)
+
+	request := &postman.Request{
+		URL:    p.requestURL(baseVariable, pathSegments, nil, nil),
+		Method: postman.Post,
+		Header: []*postman.Header{
+			{Key: "Content-Type", Value: "text/xml; charset=utf-8"},
+			{Key: "SOAPAction", Value: strconv.Quote(action)},
+		},
+		Body: &postman.Body{
+			Mode:    "raw",
+			Raw:     body,
+			Options: &postman.BodyOptions{Raw: postman.BodyOptionsRaw{Language: "xml"}},
+		},
+	}
+
+	name := action
+	if i := strings.LastIndexAny(name, "/#"); i >= 0 && i < len(name)-1 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		name = p.routeName(map[string]any{}, pathSegments)
+	}
+
+	item := postman.CreateItem(postman.Item{
+		Name:                    name,
+		Request:                 request,
+		ProtocolProfileBehavior: p.protocolProfileBehaviorFor(map[string]any{}, false),
+	})
+	folder := pathSegments
+	if isRootPath(pathSegments) {
+		folder = nil
+	}
+	if err := p.addToFolder(folder, item); err != nil {
+		return err
+	}
+
+	p.routes = append(p.routes, &route{
+		method:       "POST",
+		path:         path,
+		name:         name,
+		inputType:    envelopeType,
+		request:      request,
+		items:        []*postman.Items{item},
+		baseVariable: baseVariable,
+	})
+
+	return nil
+}
+
+// soapBody renders the example XML document of t.
+func (p *PostmanGen) soapBody(t reflect.Type) (string, error) {
+	v := reflect.New(t)
+	p.fillXMLExample(v.Elem(), map[reflect.Type]bool{})
+
+	var doc any = v.Interface()
+	if t.Name() != "Envelope" {
+		envelope := &soapEnvelope{Soap: "http://schemas.xmlsoap.org/soap/envelope/"}
+		envelope.Body.Content = doc
+		doc = envelope
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(b), nil
+}
+
+// fillXMLExample sets the fields of v to their examples, allocating pointers
+// and giving slices one element so every element appears in the document.
+func (p *PostmanGen) fillXMLExample(v reflect.Value, visiting map[reflect.Type]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if visiting[v.Type().Elem()] {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		p.fillXMLExample(v.Elem(), visiting)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		p.fillXMLExample(v.Index(0), visiting)
+	case reflect.Struct:
+		if visiting[v.Type()] || v.Type() == timeType {
+			return
+		}
+		visiting[v.Type()] = true
+		defer delete(visiting, v.Type())
+
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" || f.Type == reflect.TypeOf(xml.Name{}) {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("xml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+
+			example := f.Tag.Get("example")
+			if example == "" || example == "-" {
+				if defaultValue, ok := p.placeholderDefaults[name]; ok {
+					p.usedPlaceholders[name] = true
+					example = defaultValue
+				}
+			}
+			if example != "" && example != "-" && setFromString(v.Field(i), example) {
+				continue
+			}
+			p.fillXMLExample(v.Field(i), visiting)
+		}
+	}
+}
+
+// setFromString parses s into v, allocating pointers, and reports whether v
+// has a kind that can hold a scalar example.
+func setFromString(v reflect.Value, s string) bool {
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if !setFromString(elem.Elem(), s) {
+			return false
+		}
+		v.Set(elem)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, _ := strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, _ := strconv.ParseInt(s, 10, 64)
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, _ := strconv.ParseUint(s, 10, 64)
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, _ := strconv.ParseFloat(s, 64)
+		v.SetFloat(f)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// sseDescription is added to the description of routes registered with the
+// "sse" spec key.
+const sseDescription = "Streams Server-Sent Events: the response stays open and delivers events as they occur. Postman shows the events received until the request is cancelled."
+
+// eventStreamResponse rewrites a saved JSON response as a text/event-stream
+// carrying the example as a single event.
+func eventStreamResponse(response *postman.Response) {
+	data := bytes.Buffer{}
+	if err := json.Compact(&data, []byte(response.Body)); err != nil {
+		data.WriteString(response.Body)
+	}
+
+	response.Body = "data: " + data.String() + "\n\n"
+	response.PreviewLanguage = "text"
+	response.Headers = &postman.HeaderList{Headers: []*postman.Header{
+		{Key: "Content-Type", Value: "text/event-stream"},
+		{Key: "Cache-Control", Value: "no-cache"},
+	}}
+}
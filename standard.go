@@ -0,0 +1,90 @@
+package postmangen
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// operationsFolder is the folder AddStandardEndpoints adds its requests to.
+const operationsFolder = "Operations"
+
+// StandardEndpoint is an operational GET endpoint added by
+// AddStandardEndpoints, such as HealthCheck or Version.
+type StandardEndpoint struct {
+	Name        string
+	Path        string
+	Description string
+	// Tests are the lines of the request's test script.
+	Tests []string
+}
+
+// HealthCheck is a liveness endpoint at path, tested for a 200 response whose
+// body contains "ok".
+func HealthCheck(path string) StandardEndpoint {
+	return StandardEndpoint{
+		Name:        "Health Check",
+		Path:        path,
+		Description: "Reports whether the service is up.",
+		Tests: []string{
+			`pm.test("Service is healthy", function () {`,
+			`    pm.response.to.have.status(200);`,
+			`    pm.expect(pm.response.text().toLowerCase()).to.include("ok");`,
+			`});`,
+		},
+	}
+}
+
+// Readiness is a readiness endpoint at path, tested for a 200 response.
+func Readiness(path string) StandardEndpoint {
+	return StandardEndpoint{
+		Name:        "Readiness",
+		Path:        path,
+		Description: "Reports whether the service is ready to receive traffic.",
+		Tests: []string{
+			`pm.test("Service is ready", function () {`,
+			`    pm.response.to.have.status(200);`,
+			`});`,
+		},
+	}
+}
+
+// Version is an endpoint at path reporting the deployed version, tested for a
+// 200 response with a body.
+func Version(path string) StandardEndpoint {
+	return StandardEndpoint{
+		Name:        "Version",
+		Path:        path,
+		Description: "Reports the deployed version of the service.",
+		Tests: []string{
+			`pm.test("Version is reported", function () {`,
+			`    pm.response.to.have.status(200);`,
+			`    pm.expect(pm.response.text()).to.not.be.empty;`,
+			`});`,
+		},
+	}
+}
+
+// AddStandardEndpoints registers operational endpoints every service exposes,
+// e.g. pg.AddStandardEndpoints(HealthCheck("/healthz"), Version("/version")),
+// as GET requests in an "Operations" folder. Failures are handled like in
+// RegisterAll.
+func (p *PostmanGen) AddStandardEndpoints(endpoints ...StandardEndpoint) error {
+	errs := []error{}
+	for _, endpoint := range endpoints {
+		err := p.Register(RouteSpec{
+			"method":      http.MethodGet,
+			"path":        endpoint.Path,
+			"inputType":   reflect.TypeOf(struct{}{}),
+			"name":        endpoint.Name,
+			"description": endpoint.Description,
+			"folder":      operationsFolder,
+			"testScript":  endpoint.Tests,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("GET %v: %w", endpoint.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
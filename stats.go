@@ -0,0 +1,64 @@
+package postmangen
+
+import "github.com/rbretecher/go-postman-collection"
+
+// Stats summarizes a generated collection, e.g. for CI quality gates.
+type Stats struct {
+	// Routes counts the routes registered with Register and its variants.
+	Routes int
+	// Folders and Requests count the folders and request items in the
+	// collection, including generated items such as negative tests.
+	Folders  int
+	Requests int
+	// QueryParams and PathVariables count the parameters of all requests.
+	QueryParams   int
+	PathVariables int
+	// Bodies counts the requests with a body.
+	Bodies int
+	// Responses counts the saved example responses.
+	Responses int
+	// RoutesMissingExamples counts the routes with at least one input field
+	// without an example or placeholder.
+	RoutesMissingExamples int
+	// RoutesMissingDescriptions counts the routes without a description.
+	RoutesMissingDescriptions int
+}
+
+// Stats counts what the collection contains. Lint lists the individual
+// routes behind the missing example and description counts.
+func (p *PostmanGen) Stats() Stats {
+	stats := Stats{Routes: len(p.routes)}
+	for _, r := range p.routes {
+		if len(r.missingExamples) > 0 {
+			stats.RoutesMissingExamples++
+		}
+		if r.description == "" {
+			stats.RoutesMissingDescriptions++
+		}
+	}
+	countItems(p.collection.Items, &stats)
+	return stats
+}
+
+func countItems(items []*postman.Items, stats *Stats) {
+	for _, item := range items {
+		if item.IsGroup() {
+			stats.Folders++
+			countItems(item.Items, stats)
+			continue
+		}
+		if item.Request == nil {
+			continue
+		}
+
+		stats.Requests++
+		stats.Responses += len(item.Responses)
+		if item.Request.URL != nil {
+			stats.QueryParams += len(item.Request.URL.Query)
+			stats.PathVariables += len(item.Request.URL.Variables)
+		}
+		if item.Request.Body != nil {
+			stats.Bodies++
+		}
+	}
+}
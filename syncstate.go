@@ -0,0 +1,147 @@
+package postmangen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// syncState is the content of the sync state file, the ids given to the
+// collection, its folders, keyed by path, and its requests, keyed by endpoint
+// and name.
+type syncState struct {
+	Collection string            `json:"collection"`
+	Folders    map[string]string `json:"folders"`
+	Requests   map[string]string `json:"requests"`
+}
+
+// SetSyncState keeps the ids of the collection, its folders and requests in
+// a sidecar state file, e.g. "api.postman_state.json", and writes them in the
+// collection as its _postman_id and the id of each folder and request. The
+// Postman API matches items by id when a collection is updated, so syncing a
+// regenerated collection updates its items in place, keeping their comments
+// and forks, instead of deleting and recreating them. New items are given new
+// ids, which are saved to the file when the collection is written by Write,
+// WriteToFile, WriteYAML or Workspace.Sync; commit the file along with the
+// collection. Other renderings, such as Bytes or CheckUpToDate, only read it.
+func (p *PostmanGen) SetSyncState(path string) *PostmanGen {
+	p.syncStateFile = path
+	return p
+}
+
+// addSyncIDs writes the ids of the sync state file into a rendered
+// collection, whose items are items. If new ids are assigned, it also returns
+// the state to save with saveSyncState once the collection is written.
+func (p *PostmanGen) addSyncIDs(data []byte, items []*postman.Items) ([]byte, *syncState, error) {
+	state, err := readSyncState(p.syncStateFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := decodeOrdered(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assigned := false
+	id := func(ids map[string]string, key string) *orderedNode {
+		if ids[key] == "" {
+			ids[key] = newSyncID()
+			assigned = true
+		}
+		return &orderedNode{kind: 'v', scalar: ids[key]}
+	}
+
+	if info := memberOf(doc, "info"); info != nil {
+		if state.Collection == "" {
+			state.Collection = newSyncID()
+			assigned = true
+		}
+		setMember(info, "_postman_id", &orderedNode{kind: 'v', scalar: state.Collection})
+	}
+
+	generated := p.generatedItems(nil)
+	var walk func(tree []*postman.Items, nodes *orderedNode, folder string)
+	walk = func(tree []*postman.Items, nodes *orderedNode, folder string) {
+		for i, item := range tree {
+			if nodes == nil || i >= len(nodes.values) {
+				return
+			}
+			node := nodes.values[i]
+			if item.IsGroup() {
+				path := strings.TrimPrefix(folder+"/"+item.Name, "/")
+				setMember(node, "id", id(state.Folders, path))
+				walk(item.Items, memberOf(node, "item"), path)
+				continue
+			}
+			setMember(node, "id", id(state.Requests, generated[item].endpoint+": "+item.Name))
+		}
+	}
+	walk(items, memberOf(doc, "item"), "")
+
+	data, err = encodeCollection(doc)
+	if err != nil || !assigned {
+		return data, nil, err
+	}
+	return data, state, nil
+}
+
+// saveSyncState saves the state returned by addSyncIDs for a collection that
+// was written. A nil state has nothing new to save.
+func (p *PostmanGen) saveSyncState(state *syncState) error {
+	if state == nil {
+		return nil
+	}
+	if err := writeSyncState(p.syncStateFile, state); err != nil {
+		return err
+	}
+	p.debugf("%s: saved new sync ids", p.syncStateFile)
+	return nil
+}
+
+// readSyncState reads the sync state file at path, which may not exist yet.
+func readSyncState(path string) (*syncState, error) {
+	state := &syncState{}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("invalid sync state file %s: %w", path, err)
+		}
+	}
+	if state.Folders == nil {
+		state.Folders = map[string]string{}
+	}
+	if state.Requests == nil {
+		state.Requests = map[string]string{}
+	}
+	return state, nil
+}
+
+func writeSyncState(path string, state *syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// newSyncID returns a random UUID, the form of the ids Postman gives.
+func newSyncID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
@@ -0,0 +1,3 @@
+pm.test("Status code is {% .status %}", function () {
+    pm.response.to.have.status({% .status %});
+});
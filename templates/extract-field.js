@@ -0,0 +1,7 @@
+pm.test({% js (print "Response has " .path) %}, function () {
+    const value = {% js .path %}.split(".").reduce(function (obj, key) {
+        return obj === undefined || obj === null ? undefined : obj[key];
+    }, pm.response.json());
+    pm.expect(value, {% js .path %}).to.not.be.undefined;
+    pm.collectionVariables.set({% js .variable %}, typeof value === "object" ? JSON.stringify(value) : value);
+});
@@ -0,0 +1,16 @@
+// Run the request again with a new token when it is rejected with 401.
+{
+    const retryKey = "retry_401_" + pm.info.requestId;
+    if (pm.response.code === 401) {
+        const attempts = Number(pm.collectionVariables.get(retryKey) || 0);
+        if (attempts < {% .maxRetries %}) {
+            pm.collectionVariables.set(retryKey, String(attempts + 1));
+            pm.collectionVariables.unset({% js .tokenVariable %});
+            postman.setNextRequest(pm.info.requestName);
+        } else {
+            pm.collectionVariables.unset(retryKey);
+        }
+    } else {
+        pm.collectionVariables.unset(retryKey);
+    }
+}
@@ -0,0 +1,44 @@
+package postmangen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// PrintTree writes the folder and item hierarchy of the collection as
+// indented text, one line per folder and request (method, path and name),
+// for a quick check of the structure without reading the JSON:
+//
+//	POST /users  Create User
+//	users/
+//	  GET /users/:userId  :userId
+func (p *PostmanGen) PrintTree(w io.Writer) error {
+	b := &strings.Builder{}
+	writeTree(b, p.collection.Items, 0)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTree(b *strings.Builder, items []*postman.Items, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, item := range items {
+		if item.IsGroup() {
+			fmt.Fprintf(b, "%s%s/\n", indent, item.Name)
+			writeTree(b, item.Items, depth+1)
+			continue
+		}
+		if item.Request == nil {
+			fmt.Fprintf(b, "%s%s\n", indent, item.Name)
+			continue
+		}
+
+		path := "/"
+		if item.Request.URL != nil {
+			path += strings.Join(item.Request.URL.Path, "/")
+		}
+		fmt.Fprintf(b, "%s%s %s  %s\n", indent, item.Request.Method, path, item.Name)
+	}
+}
@@ -0,0 +1,134 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// volatileKeys are collection fields Postman adds or rewrites on import and
+// export, which are ignored when comparing collections.
+var volatileKeys = map[string]bool{
+	"_postman_id":      true,
+	"_exporter_id":     true,
+	"_collection_link": true,
+	"id":               true,
+	"uid":              true,
+	"owner":            true,
+	"createdAt":        true,
+	"updatedAt":        true,
+	"lastUpdatedBy":    true,
+}
+
+// NormalizeCollection returns a canonical form of a collection document for
+// comparisons: volatile fields such as _postman_id and ids are removed and
+// the JSON is re-indented with sorted object keys.
+func NormalizeCollection(data []byte) ([]byte, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(stripVolatile(doc), "", "  ")
+}
+
+func stripVolatile(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if volatileKeys[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = stripVolatile(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = stripVolatile(value)
+		}
+	}
+	return v
+}
+
+// CheckUpToDate regenerates the collection in memory and compares it with
+// existingFile, ignoring volatile fields. It returns an error naming the first
+// difference when the committed collection is stale, which makes it suitable
+// as a CI check. Pass the options the file was written with.
+func (p *PostmanGen) CheckUpToDate(existingFile string, opts ...WriteOption) error {
+	existing, err := os.ReadFile(existingFile)
+	if err != nil {
+		return err
+	}
+	generated, err := p.render(opts...)
+	if err != nil {
+		return err
+	}
+
+	var want, got any
+	if err := json.Unmarshal(generated, &want); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(existing, &got); err != nil {
+		return fmt.Errorf("%s: %w", existingFile, err)
+	}
+
+	if path, ok := firstDifference(stripVolatile(want), stripVolatile(got), ""); !ok {
+		return fmt.Errorf("%s is out of date: %s differs from the generated collection", existingFile, path)
+	}
+	return nil
+}
+
+// firstDifference compares two decoded JSON documents and returns the path of
+// the first difference, or false if there is none.
+func firstDifference(want any, got any, path string) (string, bool) {
+	switch want := want.(type) {
+	case map[string]any:
+		got, ok := got.(map[string]any)
+		if !ok {
+			return pathOrRoot(path), false
+		}
+		keys := []string{}
+		for key := range want {
+			keys = append(keys, key)
+		}
+		for key := range got {
+			if _, ok := want[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if diff, ok := firstDifference(want[key], got[key], joinPath(path, key)); !ok {
+				return diff, false
+			}
+		}
+		return "", true
+	case []any:
+		got, ok := got.([]any)
+		if !ok {
+			return pathOrRoot(path), false
+		}
+		for i := 0; i < len(want) && i < len(got); i++ {
+			if diff, ok := firstDifference(want[i], got[i], indexPath(path, i)); !ok {
+				return diff, false
+			}
+		}
+		if len(want) != len(got) {
+			return fmt.Sprintf("%s (length %d, generated %d)", pathOrRoot(path), len(got), len(want)), false
+		}
+		return "", true
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		return pathOrRoot(path), false
+	}
+	return "", true
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
@@ -0,0 +1,219 @@
+package postmangen
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// BaseURL describes the part of request URLs that precedes the route path.
+// Each field may be a literal or contain {{variable}} references, e.g.
+// BaseURL{Protocol: "https", Host: "{{host}}", Port: "8443", BasePath: "/v1"}.
+type BaseURL struct {
+	Protocol string
+	Host     string
+	Port     string
+	BasePath string
+}
+
+// SetBaseURL makes requests use base for their protocol, host, port and path
+// prefix, emitted into the matching fields of the Postman URL instead of a
+// single {{base_url}} host entry (see WithBaseURLVariable). The zero BaseURL restores the default.
+func (p *PostmanGen) SetBaseURL(base BaseURL) *PostmanGen {
+	p.baseURL = base
+	return p
+}
+
+// SetBaseURLVariables makes requests start with {{protocol}}://{{host}}:{{port}},
+// each part backed by a collection variable, and environment entry, defaulting
+// to the given value, for deployments whose scheme, host and port vary
+// independently. An empty port leaves the port out. The base path of SetBaseURL
+// is kept, and variables that already exist are left unchanged.
+func (p *PostmanGen) SetBaseURLVariables(protocol string, host string, port string) *PostmanGen {
+	p.baseURL.Protocol = "{{protocol}}"
+	p.baseURL.Host = "{{host}}"
+	p.baseURL.Port = ""
+	p.addPathParamVariable("protocol", protocol, "URL scheme, e.g. https")
+	p.addPathParamVariable("host", host, "Host name of the API")
+	if port != "" {
+		p.baseURL.Port = "{{port}}"
+		p.addPathParamVariable("port", port, "Port of the API")
+	}
+	return p
+}
+
+// SetFolderBaseURLVariable makes routes under pathPrefix (e.g. "/billing") use
+// {{variable}} as their base URL, for collections spanning several services.
+// The longest matching prefix wins, and a route's "baseURLVariable" spec key
+// takes precedence over folder assignments.
+func (p *PostmanGen) SetFolderBaseURLVariable(pathPrefix string, variable string) *PostmanGen {
+	p.folderBaseURLs[strings.Trim(pathPrefix, "/")] = variable
+	return p
+}
+
+// routeBaseURLVariable returns the base URL variable assigned to a route, or
+// an empty string if it uses the collection's base URL.
+func (p *PostmanGen) routeBaseURLVariable(spec map[string]any, pathSegments []string) string {
+	if variable, ok := spec["baseURLVariable"].(string); ok && variable != "" {
+		return variable
+	}
+
+	variable, _ := folderValue(p.folderBaseURLs, pathSegments)
+	return variable
+}
+
+// folderValue returns the value of the longest path prefix in folders that
+// pathSegments start with, and false if none matches.
+func folderValue[V any](folders map[string]V, pathSegments []string) (V, bool) {
+	var value V
+	longest := -1
+	for prefix, v := range folders {
+		prefixSegments := strings.Split(prefix, "/")
+		if prefix == "" {
+			prefixSegments = nil
+		}
+		if len(prefixSegments) <= longest || len(prefixSegments) > len(pathSegments) {
+			continue
+		}
+		if slices.Equal(prefixSegments, pathSegments[:len(prefixSegments)]) {
+			value = v
+			longest = len(prefixSegments)
+		}
+	}
+	return value, longest >= 0
+}
+
+// String returns the base URL as written at the start of raw URLs.
+func (b BaseURL) String() string {
+	s := ""
+	if b.Protocol != "" {
+		s += b.Protocol + "://"
+	}
+	s += b.Host
+	if b.Port != "" {
+		s += ":" + b.Port
+	}
+	if basePath := strings.Trim(b.BasePath, "/"); basePath != "" {
+		s += "/" + basePath
+	}
+	return s
+}
+
+// requestURL builds the URL of a request for the route path made of
+// pathSegments. A non-empty baseVariable replaces the configured base URL.
+func (p *PostmanGen) requestURL(baseVariable string, pathSegments []string, query []*postman.QueryParam, variables []*postman.Variable) *postman.URL {
+	if baseVariable != "" || p.baseURL == (BaseURL{}) {
+		if baseVariable == "" {
+			baseVariable = p.baseURLVariable
+		}
+		return &postman.URL{
+			Raw:       "{{" + baseVariable + "}}" + p.rawPathAndQuery(pathSegments, query),
+			Host:      []string{"{{" + baseVariable + "}}"},
+			Path:      pathSegments,
+			Query:     query,
+			Variables: variables,
+		}
+	}
+
+	path := []string{}
+	if basePath := strings.Trim(p.baseURL.BasePath, "/"); basePath != "" {
+		path = append(path, strings.Split(basePath, "/")...)
+	}
+	path = append(path, pathSegments...)
+
+	return &postman.URL{
+		Raw:       p.baseURL.String() + p.rawPathAndQuery(pathSegments, query),
+		Protocol:  p.baseURL.Protocol,
+		Host:      splitHost(p.baseURL.Host),
+		Port:      p.baseURL.Port,
+		Path:      path,
+		Query:     query,
+		Variables: variables,
+	}
+}
+
+// splitHost splits a host name into the labels Postman expects. Hosts made of
+// a single variable reference are kept whole.
+func splitHost(host string) []string {
+	if host == "" {
+		return nil
+	}
+	if strings.HasPrefix(host, "{{") && strings.HasSuffix(host, "}}") {
+		return []string{host}
+	}
+	return strings.Split(host, ".")
+}
+
+// baseURLValue returns the base URL requests are sent to, with collection
+// variables resolved.
+func (p *PostmanGen) baseURLValue() string {
+	if p.baseURL == (BaseURL{}) {
+		return p.variableValue(p.baseURLVariable)
+	}
+	base := p.baseURL
+	base.BasePath = ""
+	return p.resolveVariables(base.String())
+}
+
+// rawPathAndQuery returns the percent-encoded path and query string of a raw
+// URL. Disabled query parameters are left out, as Postman does.
+func (p *PostmanGen) rawPathAndQuery(pathSegments []string, query []*postman.QueryParam) string {
+	raw := escapePath(pathSegments)
+	enabled := []*postman.QueryParam{}
+	for _, q := range query {
+		if !p.isDisabledQuery(q) {
+			enabled = append(enabled, q)
+		}
+	}
+	if queryString := exampleQuery(enabled); queryString != "" {
+		raw += "?" + queryString
+	}
+	return raw
+}
+
+// escapePath joins path segments into a percent-encoded path. Slashes inside
+// segments, e.g. in catch-all values, are kept, and so are the separators of
+// the matrix parameters of the last segment.
+func escapePath(segments []string) string {
+	last := len(segments) - 1
+	for last > 0 && segments[last] == "" {
+		last--
+	}
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		parts := strings.Split(segment, "/")
+		for j, part := range parts {
+			if i == last && j == len(parts)-1 {
+				parts[j] = escapeMatrixSegment(part)
+			} else {
+				parts[j] = escapeURLPart(part, url.PathEscape)
+			}
+		}
+		escaped[i] = strings.Join(parts, "/")
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+// escapeQueryComponent percent-encodes a query key or value, writing spaces
+// as %20 like Postman does.
+func escapeQueryComponent(s string) string {
+	return escapeURLPart(s, func(s string) string {
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	})
+}
+
+// escapeURLPart applies escape to s, leaving {{variable}} references intact
+// so Postman can still substitute them.
+func escapeURLPart(s string, escape func(string) string) string {
+	b := strings.Builder{}
+	last := 0
+	for _, loc := range variableReference.FindAllStringIndex(s, -1) {
+		b.WriteString(escape(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(escape(s[last:]))
+	return b.String()
+}
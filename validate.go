@@ -0,0 +1,104 @@
+package postmangen
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldRules are the constraints of a `validate:"..."` tag (go-playground/validator
+// syntax) that postmangen knows how to document and exercise.
+type fieldRules struct {
+	Required bool
+	// Min and Max bound the length of strings and slices, or the value of
+	// numbers. They are exclusive when set from gt and lt.
+	Min          *float64
+	Max          *float64
+	MinExclusive bool
+	MaxExclusive bool
+	Len          *float64
+	OneOf        []string
+	Email        bool
+	// Pattern is the regular expression of a regexp rule or pattern tag.
+	Pattern string
+}
+
+func (r fieldRules) empty() bool {
+	return !r.Required && r.Min == nil && r.Max == nil && r.Len == nil && len(r.OneOf) == 0 && !r.Email && r.Pattern == ""
+}
+
+// parseValidateTag parses the rules of a validate tag. Rules after "dive" apply
+// to slice elements and alternatives separated by "|" are ambiguous, so both
+// are ignored. The pattern of a regexp rule spells commas and bars as 0x2C
+// and 0x7C, as in validator's escaping.
+func parseValidateTag(tag string) fieldRules {
+	rules := fieldRules{}
+
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+		if pattern, ok := strings.CutPrefix(rule, "regexp="); ok {
+			rules.Pattern = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(pattern)
+			continue
+		}
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			rules.Required = true
+		case "min", "gte":
+			rules.Min = parseRuleNumber(param)
+		case "max", "lte":
+			rules.Max = parseRuleNumber(param)
+		case "gt":
+			rules.Min = parseRuleNumber(param)
+			rules.MinExclusive = true
+		case "lt":
+			rules.Max = parseRuleNumber(param)
+			rules.MaxExclusive = true
+		case "len":
+			rules.Len = parseRuleNumber(param)
+		case "oneof":
+			rules.OneOf = strings.Fields(param)
+		case "email":
+			rules.Email = true
+		}
+	}
+
+	return rules
+}
+
+func parseRuleNumber(s string) *float64 {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func fieldValidateRules(f reflect.StructField) fieldRules {
+	rules := parseValidateTag(f.Tag.Get("validate"))
+	if pattern := f.Tag.Get("pattern"); pattern != "" {
+		rules.Pattern = pattern
+	}
+	return rules
+}
+
+// isNumberKind reports whether values of kind are rendered as JSON numbers.
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
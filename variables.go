@@ -0,0 +1,109 @@
+package postmangen
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// VariableOption configures a collection variable added with AddVariable.
+type VariableOption func(*postman.Variable)
+
+// WithDisabled adds the variable disabled, so Postman lists it without
+// resolving it, e.g. for an alternative value kept at hand.
+func WithDisabled() VariableOption {
+	return func(v *postman.Variable) {
+		v.Disabled = true
+	}
+}
+
+// WithDescription sets the description shown next to the variable in
+// Postman's variables panel.
+func WithDescription(description string) VariableOption {
+	return func(v *postman.Variable) {
+		v.Description = description
+	}
+}
+
+// AddBoolVariable adds a collection variable of the Postman boolean type.
+func (p *PostmanGen) AddBoolVariable(key string, value bool, opts ...VariableOption) *PostmanGen {
+	return p.AddVariable(key, value, opts...)
+}
+
+// AddNumberVariable adds a collection variable of the Postman number type.
+func (p *PostmanGen) AddNumberVariable(key string, value float64, opts ...VariableOption) *PostmanGen {
+	return p.AddVariable(key, value, opts...)
+}
+
+// AddVariables adds a string collection variable per entry of variables, in
+// key order.
+func (p *PostmanGen) AddVariables(variables map[string]string) *PostmanGen {
+	for _, key := range slices.Sorted(maps.Keys(variables)) {
+		p.AddVariable(key, variables[key])
+	}
+	return p
+}
+
+// AddVariablesFromEnv adds a collection variable per process environment
+// variable starting with prefix, named after the rest of its name in lower
+// case, e.g. AddVariablesFromEnv("POSTMAN_") turns POSTMAN_BASE_URL into
+// base_url. Variables that already exist take the environment value, so CI can
+// override the defaults set in code.
+func (p *PostmanGen) AddVariablesFromEnv(prefix string) *PostmanGen {
+	env := map[string]string{}
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if key, ok := strings.CutPrefix(name, prefix); ok && key != "" {
+			env[strings.ToLower(key)] = value
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		i := slices.IndexFunc(p.collection.Variables, func(v *postman.Variable) bool { return v.Key == key })
+		if i < 0 {
+			p.AddVariable(key, env[key])
+			continue
+		}
+		p.collection.Variables[i].Value = env[key]
+	}
+	return p
+}
+
+// collectionVariable returns the collection variable key holding value, whose
+// Postman type is inferred from its Go type: strings are "string", booleans
+// "boolean" and integers and floats "number". Other values are written as
+// JSON with the "any" type.
+func collectionVariable(key string, value any) *postman.Variable {
+	v := &postman.Variable{Key: key, Type: "any"}
+	if value == nil {
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		v.Type, v.Value = "string", rv.String()
+	case reflect.Bool:
+		v.Type, v.Value = "boolean", strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.Type, v.Value = "number", strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.Type, v.Value = "number", strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		v.Type, v.Value = "number", strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	default:
+		if b, err := json.Marshal(value); err == nil {
+			v.Value = string(b)
+		} else {
+			v.Value = fmt.Sprint(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,179 @@
+package postmangen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// VerifyResult is the outcome of calling one registered route on a server.
+type VerifyResult struct {
+	Method string
+	Path   string
+	URL    string
+	Status int
+	// Problem describes why the result is unexpected; it is empty when the
+	// route behaved as registered.
+	Problem string
+}
+
+// OK reports whether the route behaved as registered.
+func (r VerifyResult) OK() bool {
+	return r.Problem == ""
+}
+
+// Verify sends the example request of every registered route to the server at
+// baseURL and reports routes that look out of sync with it: 404 and 405
+// responses (no handler), 5xx responses, and statuses that are not among the
+// route's declared responses. Collection variables referenced by the requests,
+// such as {{token}}, are substituted with their values, and routes with their
+// own base URL variable are sent to its value when it is defined. The returned error
+// joins the problems of all failing routes. A nil client uses
+// http.DefaultClient.
+func (p *PostmanGen) Verify(baseURL string, client *http.Client) ([]VerifyResult, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	results := []VerifyResult{}
+	errs := []error{}
+
+	for _, r := range p.routes {
+		result := VerifyResult{Method: strings.ToUpper(r.method), Path: r.path}
+
+		req, err := p.verifyRequest(baseURL, r)
+		if err != nil {
+			result.Problem = err.Error()
+		} else {
+			result.URL = req.URL.String()
+
+			resp, err := client.Do(req)
+			if err != nil {
+				result.Problem = err.Error()
+			} else {
+				// Event streams never end, so only their status is read.
+				if !r.stream {
+					io.Copy(io.Discard, resp.Body)
+				}
+				resp.Body.Close()
+				result.Status = resp.StatusCode
+				result.Problem = unexpectedStatus(r, resp.StatusCode)
+			}
+		}
+
+		if !result.OK() {
+			errs = append(errs, fmt.Errorf("%s %s: %s", result.Method, result.Path, result.Problem))
+		}
+		results = append(results, result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func unexpectedStatus(r *route, status int) string {
+	switch {
+	case status == http.StatusNotFound:
+		return "route not found (404)"
+	case status == http.StatusMethodNotAllowed:
+		return "method not allowed (405)"
+	case status >= 500:
+		return "server error (" + statusLabel(status) + ")"
+	}
+
+	if len(r.responses) == 0 {
+		return ""
+	}
+	declared := []int{}
+	for _, resp := range r.responses {
+		declared = append(declared, resp.status)
+	}
+	if !slices.Contains(declared, status) {
+		return fmt.Sprintf("unexpected status %d, declared %v", status, declared)
+	}
+	return ""
+}
+
+// verifyRequest builds the HTTP request for the example of r.
+func (p *PostmanGen) verifyRequest(baseURL string, r *route) (*http.Request, error) {
+	if r.baseVariable != "" {
+		if value := p.variableValue(r.baseVariable); value != "" {
+			baseURL = strings.TrimRight(p.resolveVariables(value), "/")
+		}
+	}
+
+	u, err := url.Parse(baseURL + p.resolveVariables(examplePath(r.request.URL)))
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	for _, q := range p.enabledQuery(r.request.URL) {
+		query.Add(q.Key, p.resolveVariables(q.Value))
+	}
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	contentType := ""
+	if r.request.Body != nil {
+		switch r.request.Body.Mode {
+		case "raw", "graphql":
+			text, _ := bodyText(r.request.Body)
+			body = strings.NewReader(p.resolveVariables(text))
+		case "formdata":
+			buf := &bytes.Buffer{}
+			mw := multipart.NewWriter(buf)
+			for _, field := range formFields(r.request.Body.FormData) {
+				if field.Type != "file" {
+					mw.WriteField(field.Key, p.resolveVariables(field.Value))
+				}
+			}
+			mw.Close()
+			body = buf
+			contentType = mw.FormDataContentType()
+		}
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(r.method), u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range r.request.Header {
+		req.Header.Set(h.Key, p.resolveVariables(h.Value))
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if auth := p.collection.Auth; auth != nil && auth.Type == "bearer" {
+		for _, param := range auth.Bearer {
+			if param.Key == "token" {
+				req.Header.Set("Authorization", "Bearer "+p.resolveVariables(fmt.Sprint(param.Value)))
+			}
+		}
+	}
+
+	return req, nil
+}
+
+var variableReference = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// resolveVariables substitutes {{key}} references to collection variables in
+// s. References to unknown or disabled variables are left untouched.
+func (p *PostmanGen) resolveVariables(s string) string {
+	return variableReference.ReplaceAllStringFunc(s, func(ref string) string {
+		key := ref[2 : len(ref)-2]
+		for _, v := range p.collection.Variables {
+			if v.Key == key && !v.Disabled {
+				return v.Value
+			}
+		}
+		return ref
+	})
+}
@@ -0,0 +1,132 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// changelogEntry is a version noted with AddChangelog.
+type changelogEntry struct {
+	version string
+	notes   string
+}
+
+// SetVersion sets the collection version, so importers can tell which API
+// version the collection targets. Semantic versions work with BumpVersion.
+func (p *PostmanGen) SetVersion(version string) *PostmanGen {
+	p.collection.Info.Version = version
+	return p
+}
+
+// AddChangelog notes the changes of a version in a "Changelog" section of the
+// collection description. Entries are listed in the order they are added.
+func (p *PostmanGen) AddChangelog(version string, notes string) *PostmanGen {
+	p.changelog = append(p.changelog, changelogEntry{version: version, notes: notes})
+	return p
+}
+
+// changelogNotes returns the description section listing the changelog, or
+// an empty string if there is none.
+func (p *PostmanGen) changelogNotes() string {
+	if len(p.changelog) == 0 {
+		return ""
+	}
+
+	b := strings.Builder{}
+	b.WriteString("\n\n## Changelog\n")
+	for _, entry := range p.changelog {
+		b.WriteString("\n### " + entry.version + "\n\n" + strings.TrimSpace(entry.notes) + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// BumpVersion compares the collection with a previously generated one, as
+// Diff does, and sets the version to the previous collection's version,
+// incremented if anything changed: the major version for breaking changes,
+// the minor version when endpoints or fields were added, and the patch
+// version otherwise. A changelog entry listing the differences is added for
+// the new version. A previous collection without a version counts as 0.0.0.
+// Write the collection WithContentHashes so removing an optional field only
+// bumps the patch version, see Change.Breaking.
+func (p *PostmanGen) BumpVersion(previous io.Reader) (DiffReport, error) {
+	data, err := io.ReadAll(previous)
+	if err != nil {
+		return DiffReport{}, err
+	}
+	var doc struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return DiffReport{}, fmt.Errorf("failed to read previous collection: %w", err)
+	}
+
+	prefix, version, err := parseSemver(doc.Info.Version)
+	if err != nil {
+		return DiffReport{}, err
+	}
+
+	report, err := p.Diff(bytes.NewReader(data))
+	if err != nil {
+		return DiffReport{}, err
+	}
+
+	switch {
+	case report.HasBreakingChanges():
+		version = [3]int{version[0] + 1, 0, 0}
+	case slices.ContainsFunc(report.Changes, func(c Change) bool { return c.Kind == RouteAdded || c.Kind == FieldAdded }):
+		version = [3]int{version[0], version[1] + 1, 0}
+	case len(report.Changed) > 0:
+		version[2]++
+	}
+	next := fmt.Sprintf("%s%d.%d.%d", prefix, version[0], version[1], version[2])
+	p.SetVersion(next)
+	if report.HasChanges() {
+		p.AddChangelog(next, report.changelog())
+	}
+	return report, nil
+}
+
+// parseSemver parses a MAJOR.MINOR.PATCH version with an optional "v" prefix.
+func parseSemver(version string) (prefix string, parts [3]int, err error) {
+	if version == "" {
+		return "", parts, nil
+	}
+	if strings.HasPrefix(version, "v") {
+		prefix, version = "v", version[1:]
+	}
+
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return "", parts, fmt.Errorf("invalid version %q: must be MAJOR.MINOR.PATCH", prefix+version)
+	}
+	for i, field := range fields {
+		parts[i], err = strconv.Atoi(field)
+		if err != nil || parts[i] < 0 {
+			return "", parts, fmt.Errorf("invalid version %q: must be MAJOR.MINOR.PATCH", prefix+version)
+		}
+	}
+	return prefix, parts, nil
+}
+
+// changelog returns the changes of the report as markdown list items.
+func (r DiffReport) changelog() string {
+	b := strings.Builder{}
+	for _, c := range r.Changes {
+		b.WriteString("- `" + c.Endpoint + "`: " + string(c.Kind))
+		if c.Field != "" {
+			b.WriteString(" `" + c.Field + "`")
+		}
+		if c.Breaking {
+			b.WriteString(" (breaking)")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
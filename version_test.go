@@ -0,0 +1,86 @@
+package postmangen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		previous *PostmanGen
+		current  *PostmanGen
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "unchanged",
+			version:  "1.2.3",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUser{}, "/users"),
+			want:     "1.2.3",
+		},
+		{
+			name:     "no previous version",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUserWithEmail{}, "/users"),
+			want:     "0.1.0",
+		},
+		{
+			name:     "breaking change",
+			version:  "1.2.3",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUserWithoutName{}, "/users"),
+			want:     "2.0.0",
+		},
+		{
+			name:     "route added",
+			version:  "v1.2.3",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUser{}, "/users", "/admins"),
+			want:     "v1.3.0",
+		},
+		{
+			name:     "optional field removed",
+			version:  "1.2.3",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUserWithoutNickname{}, "/users"),
+			want:     "1.2.4",
+		},
+		{
+			name:     "invalid version",
+			version:  "1.2",
+			previous: generatorWithRoutes(t, diffUser{}, "/users"),
+			current:  generatorWithRoutes(t, diffUser{}, "/users"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous, err := tt.previous.SetVersion(tt.version).Bytes(WithContentHashes())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := tt.current.BumpVersion(bytes.NewReader(previous))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("BumpVersion() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.current.collection.Info.Version; got != tt.want {
+				t.Errorf("version = %q, want %q", got, tt.want)
+			}
+			hasEntry := strings.Contains(tt.current.changelogNotes(), "### "+tt.want+"\n")
+			if hasEntry != report.HasChanges() {
+				t.Errorf("changelog entry for %s = %v, want %v", tt.want, hasEntry, report.HasChanges())
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package postmangen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rbretecher/go-postman-collection"
+)
+
+// Workspace manages the collections of several services, e.g. in a monorepo,
+// with variables shared by all of them and a single export step.
+type Workspace struct {
+	name        string
+	collections []*PostmanGen
+	variables   []*postman.Variable
+	sensitive   []string
+}
+
+// NewWorkspace returns an empty workspace. Its name is used for the shared
+// environment.
+func NewWorkspace(name string) *Workspace {
+	return &Workspace{name: name}
+}
+
+// Add adds collections to the workspace.
+func (w *Workspace) Add(collections ...*PostmanGen) *Workspace {
+	for _, p := range collections {
+		p.MarkSensitive(w.sensitive...)
+		w.collections = append(w.collections, p)
+	}
+	return w
+}
+
+// Collections returns the collections of the workspace in the order they were
+// added.
+func (w *Workspace) Collections() []*PostmanGen {
+	return slices.Clone(w.collections)
+}
+
+// AddVariable adds a variable shared by all collections, such as a gateway URL
+// or token. It is written into every collection that does not define a
+// variable with the same key, and into the shared environment. Its Postman
+// type is inferred from value and options can disable or describe it, see
+// PostmanGen.AddVariable.
+func (w *Workspace) AddVariable(key string, value any, opts ...VariableOption) *Workspace {
+	v := collectionVariable(key, value)
+	for _, opt := range opts {
+		opt(v)
+	}
+	w.variables = append(w.variables, v)
+	return w
+}
+
+// MarkSensitive flags variables as sensitive in all collections and the
+// shared environment, see PostmanGen.MarkSensitive.
+func (w *Workspace) MarkSensitive(keys ...string) *Workspace {
+	w.sensitive = append(w.sensitive, keys...)
+	for _, p := range w.collections {
+		p.MarkSensitive(keys...)
+	}
+	return w
+}
+
+// Sync writes every collection to dir as <name>.postman_collection.json and
+// the shared variables as <workspace>.postman_environment.json, where names
+// are lowercased with other characters than letters and digits replaced by
+// dashes. Files whose content is unchanged are not rewritten, so their
+// modification times stay meaningful; the names of the written files are
+// returned. The write options apply to all files.
+func (w *Workspace) Sync(dir string, opts ...WriteOption) (written []string, err error) {
+	files := map[string][]byte{}
+	names := []string{}
+	states := map[*PostmanGen]*syncState{}
+
+	opts = append(slices.Clone(opts), func(s *writeSettings) {
+		s.sharedVariables = w.variables
+	})
+	for _, p := range w.collections {
+		name := fileName(p.collection.Info.Name) + ".postman_collection.json"
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("collections %q share the file name %s", p.collection.Info.Name, name)
+		}
+		data, state, err := p.renderForWrite(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		files[name] = data
+		names = append(names, name)
+		states[p] = state
+	}
+
+	env := NewPostmanGen(w.name, "")
+	env.collection.Variables = w.variables
+	env.MarkSensitive(w.sensitive...)
+	name := fileName(w.name) + ".postman_environment.json"
+	data, err := env.environmentBytes(opts...)
+	if err != nil {
+		return nil, err
+	}
+	files[name] = data
+	names = append(names, name)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	written = []string{}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, files[name]) {
+			continue
+		}
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, name)
+	}
+	for _, p := range w.collections {
+		if err := p.saveSyncState(states[p]); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// addSharedVariables adds the variables the collection does not define, and
+// returns a function removing them again.
+func (p *PostmanGen) addSharedVariables(shared []*postman.Variable) (restore func()) {
+	original := p.collection.Variables
+	for _, v := range shared {
+		if slices.ContainsFunc(original, func(own *postman.Variable) bool { return own.Key == v.Key }) {
+			continue
+		}
+		copied := *v
+		p.collection.Variables = append(p.collection.Variables, &copied)
+	}
+	return func() {
+		p.collection.Variables = original
+	}
+}
+
+// fileName returns name lowercased, with runs of characters other than
+// letters and digits replaced by a dash.
+func fileName(name string) string {
+	b := strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "collection"
+	}
+	return b.String()
+}
@@ -0,0 +1,307 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteYAML writes the collection as YAML, for teams that review API
+// definitions in YAML. The document has the same structure and key order as
+// the JSON written by Write; YAMLToJSON converts it back for importing into
+// Postman.
+func (p *PostmanGen) WriteYAML(w io.Writer, opts ...WriteOption) error {
+	data, state, err := p.renderForWrite(opts...)
+	if err != nil {
+		return err
+	}
+
+	node, err := decodeOrdered(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(node)); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return p.saveSyncState(state)
+}
+
+// YAMLToJSON converts a collection written by WriteYAML back to the JSON
+// Postman imports, keeping the key order. It reads any YAML document whose
+// mapping keys are scalars, expanding aliases; merge keys and values with no
+// JSON form, such as .inf, are rejected.
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return []byte("null"), nil
+	}
+	node, err := orderedYAML(doc.Content[0], map[*yaml.Node]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeOrderedJSON(buf, node); err != nil {
+		return nil, err
+	}
+	out := &bytes.Buffer{}
+	if err := json.Indent(out, buf.Bytes(), "", "    "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// orderedNode is a JSON value that keeps the order of object members.
+type orderedNode struct {
+	kind   byte // 'o' object, 'a' array, 'v' scalar
+	keys   []string
+	values []*orderedNode
+	// scalar is a string, json.Number, bool or nil.
+	scalar any
+}
+
+// decodeOrdered reads the next JSON value from dec, keeping member order.
+func decodeOrdered(dec *json.Decoder) (*orderedNode, error) {
+	dec.UseNumber()
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		node := &orderedNode{kind: 'o'}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.keys = append(node.keys, key.(string))
+			node.values = append(node.values, value)
+		}
+		_, err = dec.Token()
+		return node, err
+	case json.Delim('['):
+		node := &orderedNode{kind: 'a'}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, value)
+		}
+		_, err = dec.Token()
+		return node, err
+	}
+	return &orderedNode{kind: 'v', scalar: token}, nil
+}
+
+// writeOrderedJSON writes node as compact JSON.
+func writeOrderedJSON(buf *bytes.Buffer, node *orderedNode) error {
+	switch node.kind {
+	case 'o':
+		buf.WriteByte('{')
+		for i, key := range node.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeOrderedJSON(buf, node.values[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case 'a':
+		buf.WriteByte('[')
+		for i, value := range node.values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeOrderedJSON(buf, value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		switch v := node.scalar.(type) {
+		case string:
+			return writeJSONString(buf, v)
+		case bool:
+			buf.Write(strconv.AppendBool(buf.AvailableBuffer(), v))
+			return nil
+		case int:
+			buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(v), 10))
+			return nil
+		case int64:
+			buf.Write(strconv.AppendInt(buf.AvailableBuffer(), v, 10))
+			return nil
+		case nil:
+			buf.WriteString("null")
+			return nil
+		}
+		b, err := json.Marshal(node.scalar)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
+
+// writeJSONString writes s as a JSON string like json.Marshal, without
+// allocating for strings that need no escaping.
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			b, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			return nil
+		}
+	}
+	buf.WriteByte('"')
+	buf.WriteString(s)
+	buf.WriteByte('"')
+	return nil
+}
+
+// yamlNode converts node to a YAML node with the same member order.
+// Multi-line strings are written as literal block scalars, keeping scripts
+// and bodies readable.
+func yamlNode(node *orderedNode) *yaml.Node {
+	switch node.kind {
+	case 'o':
+		out := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for i, key := range node.keys {
+			out.Content = append(out.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, yamlNode(node.values[i]))
+		}
+		return out
+	case 'a':
+		out := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, value := range node.values {
+			out.Content = append(out.Content, yamlNode(value))
+		}
+		return out
+	}
+
+	switch v := node.scalar.(type) {
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case json.Number:
+		tag := "!!float"
+		if _, err := v.Int64(); err == nil {
+			tag = "!!int"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case string:
+		out := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		switch {
+		case strings.Contains(v, "\n"):
+			out.Style = yaml.LiteralStyle
+		case yaml11Bool(v):
+			out.Style = yaml.DoubleQuotedStyle
+		}
+		return out
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(node.scalar)}
+}
+
+// yaml11Bool reports whether s is a boolean for YAML 1.1 parsers, such as
+// y or off, which yaml.v3 would leave unquoted.
+func yaml11Bool(s string) bool {
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off":
+		return true
+	}
+	return false
+}
+
+// orderedYAML converts a decoded YAML node to an orderedNode. aliased holds
+// the anchored nodes being converted, so recursive aliases are reported
+// instead of looping.
+func orderedYAML(node *yaml.Node, aliased map[*yaml.Node]bool) (*orderedNode, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		if aliased[node.Alias] {
+			return nil, fmt.Errorf("yaml: line %d: alias *%s refers to itself", node.Line, node.Value)
+		}
+		aliased[node.Alias] = true
+		defer delete(aliased, node.Alias)
+		return orderedYAML(node.Alias, aliased)
+	case yaml.MappingNode:
+		out := &orderedNode{kind: 'o'}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("yaml: line %d: unsupported non-scalar mapping key", key.Line)
+			}
+			if key.ShortTag() == "!!merge" {
+				return nil, fmt.Errorf("yaml: line %d: unsupported merge key", key.Line)
+			}
+			member, err := orderedYAML(value, aliased)
+			if err != nil {
+				return nil, err
+			}
+			out.keys = append(out.keys, key.Value)
+			out.values = append(out.values, member)
+		}
+		return out, nil
+	case yaml.SequenceNode:
+		out := &orderedNode{kind: 'a'}
+		for _, value := range node.Content {
+			element, err := orderedYAML(value, aliased)
+			if err != nil {
+				return nil, err
+			}
+			out.values = append(out.values, element)
+		}
+		return out, nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return &orderedNode{kind: 'v'}, nil
+		case "!!bool", "!!int", "!!float":
+			var v any
+			if err := node.Decode(&v); err != nil {
+				return nil, err
+			}
+			if f, ok := v.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+				return nil, fmt.Errorf("yaml: line %d: %s has no JSON form", node.Line, node.Value)
+			}
+			return &orderedNode{kind: 'v', scalar: v}, nil
+		}
+		return &orderedNode{kind: 'v', scalar: node.Value}, nil
+	}
+	return nil, fmt.Errorf("yaml: line %d: unsupported node", node.Line)
+}
@@ -0,0 +1,94 @@
+package postmangen
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteYAMLRoundTrip(t *testing.T) {
+	p := NewPostmanGen("API", "First line\nsecond line\n")
+	for _, path := range []string{"/users/:id", "/off/:id"} {
+		err := p.Register(RouteSpec{
+			"method":       "POST",
+			"path":         path,
+			"inputType":    reflect.TypeOf(benchRequest{}),
+			"responseType": reflect.TypeOf(benchResponse{}),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := p.WriteYAML(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "content: |\n") {
+		t.Errorf("multi-line description is not a literal block:\n%s", buf)
+	}
+	if !strings.Contains(buf.String(), `- "off"`) {
+		t.Errorf("YAML 1.1 boolean is not quoted:\n%s", buf)
+	}
+
+	back, err := YAMLToJSON(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, wantDoc any
+	if err := json.Unmarshal(back, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(want, &wantDoc); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, wantDoc) {
+		t.Errorf("YAMLToJSON(WriteYAML()) differs from Bytes():\n%s", back)
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr string
+	}{
+		{name: "empty", yaml: "", want: `null`},
+		{name: "key order", yaml: "b: 1\na: [x, {k: v}]\n", want: `{"b":1,"a":["x",{"k":"v"}]}`},
+		{name: "scalars", yaml: "s: \"1\"\ni: 1\nf: 1.5\nt: true\nn: null\nq: 'off'\n", want: `{"s":"1","i":1,"f":1.5,"t":true,"n":null,"q":"off"}`},
+		{name: "literal block", yaml: "script: |\n  a\n  b\n", want: `{"script":"a\nb\n"}`},
+		{name: "alias", yaml: "a: &x {k: v}\nb: *x\n", want: `{"a":{"k":"v"},"b":{"k":"v"}}`},
+		{name: "merge key", yaml: "a: &x {k: v}\nb:\n  <<: *x\n", wantErr: "unsupported merge key"},
+		{name: "non-scalar key", yaml: "? [a]\n: v\n", wantErr: "unsupported non-scalar mapping key"},
+		{name: "infinity", yaml: "a: .inf\n", wantErr: "has no JSON form"},
+		{name: "invalid", yaml: "a: [b\n", wantErr: "yaml:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := YAMLToJSON([]byte(tt.yaml))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("YAMLToJSON() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			compact := &bytes.Buffer{}
+			if err := json.Compact(compact, got); err != nil {
+				t.Fatal(err)
+			}
+			if compact.String() != tt.want {
+				t.Errorf("YAMLToJSON() = %s, want %s", compact, tt.want)
+			}
+		})
+	}
+}